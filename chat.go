@@ -0,0 +1,111 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+//Chat a giant bomb API chat
+type Chat struct {
+	APIDetailURL  string `json:"api_detail_url"`
+	SiteDetailURL string `json:"site_detail_url"`
+	ChannelName   string `json:"channel_name"`
+	Deck          string `json:"deck"`
+	GUID          GUID   `json:"guid"`
+	ID            int    `json:"id"`
+	Image         Image  `json:"image"`
+	Password      string `json:"password"`
+	Title         string `json:"title"`
+}
+
+//ChatsResponse chats response from giant bomb API
+type ChatsResponse struct {
+	ResponsePage
+	Results []Chat `json:"results"`
+}
+
+//Path returns chats path
+func (c *ChatsResponse) Path() (string, map[string]string) {
+	return "api/chats", make(map[string]string)
+}
+
+//Parse parse
+func (c *ChatsResponse) Parse(data []byte) error {
+	var tmp ChatsResponse
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	c.ResponsePage = tmp.ResponsePage
+	c.Results = tmp.Results
+
+	return nil
+}
+
+//GetChats returns a page of archived chats
+func (i *Invoker) GetChats(ctx context.Context, offset int) (*ChatsResponse, error) {
+	result := &ChatsResponse{}
+	result.Offset = offset
+
+	body, err := i.GetContext(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type chatResponseInternal struct {
+	ResponsePage
+	Results    *Chat `json:"results"`
+	targetChat GUID
+}
+
+//Path returns chat path
+func (c *chatResponseInternal) Path() (string, map[string]string) {
+	return fmt.Sprintf("api/chat/%s", c.targetChat), make(map[string]string)
+}
+
+//Parse parse
+func (c *chatResponseInternal) Parse(data []byte) error {
+	var tmp chatResponseInternal
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	c.ResponsePage = tmp.ResponsePage
+	c.Results = tmp.Results
+
+	return nil
+}
+
+//GetChat returns a given chat
+func (i *Invoker) GetChat(ctx context.Context, chatID GUID) (*Chat, error) {
+	if err := i.checkGUID(chatID, "chat"); err != nil {
+		return nil, err
+	}
+
+	result := &chatResponseInternal{}
+
+	result.targetChat = chatID
+
+	body, err := i.GetContext(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Results, nil
+}
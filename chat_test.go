@@ -0,0 +1,32 @@
+package gbomb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetChats(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &FileMock{
+		expectedURL: "https://www.giantbomb.com/api/chats?api_key=coolbeans&format=json&offset=0",
+		file:        "test_data/chats.json",
+	}
+	result, err := invoker.GetChats(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Results) != 2 {
+		t.Fatalf(
+			"invalid number of chats pulled %d expected %d",
+			len(result.Results), 2,
+		)
+	}
+
+	if result.Results[0].ChannelName != "giantbomb-unprofessional-fridays" {
+		t.Errorf(
+			"invalid channel name %s expected %s",
+			result.Results[0].ChannelName, "giantbomb-unprofessional-fridays",
+		)
+	}
+}
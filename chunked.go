@@ -0,0 +1,231 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+const (
+	defaultChunkWorkers = 4
+	defaultChunkSize    = 8 * 1024 * 1024
+)
+
+//ChunkedDownloadOptions options for DownloadVideoChunked
+type ChunkedDownloadOptions struct {
+	//Workers how many chunks are fetched at once, 4 if unset
+	Workers int
+	//ChunkSize bytes per range request, 8MiB if unset
+	ChunkSize int64
+	//Progress called as data is written with the bytes written and total size
+	Progress func(written, total int64)
+}
+
+//ChunksSuffix appended to the destination path while a chunked download is in
+//progress, ranges are written out of order so unlike PartialSuffix files these
+//are never resumed
+const ChunksSuffix = ".chunks"
+
+type byteRange struct {
+	start int64
+	end   int64
+}
+
+//DownloadVideoChunked downloads a video to path fetching byte ranges
+//concurrently, servers without Accept-Ranges fall back to DownloadVideoTo.
+//The rate limiter is waited on once for the whole download rather than per
+//range and a failed download removes its partial file
+func (i *Invoker) DownloadVideoChunked(ctx context.Context, video *VideoInfo, quality Quality, path string, opts *ChunkedDownloadOptions) error {
+	if opts == nil {
+		opts = &ChunkedDownloadOptions{}
+	}
+	if !quality.Downloadable() {
+		return fmt.Errorf("%s is not a direct download", quality)
+	}
+
+	url := video.QualityURL(quality)
+	if url == "" {
+		return fmt.Errorf("video %s has no %s url", video.GUID, quality)
+	}
+
+	size, ranged, err := i.probeRanges(ctx, url)
+	if err != nil {
+		return err
+	}
+	if !ranged || size <= 0 {
+		return i.downloadFileTo(ctx, url, path, &DownloadOptions{Progress: opts.Progress})
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultChunkWorkers
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	partial := path + ChunksSuffix
+	err = i.downloadChunksTo(ctx, url, size, partial, workers, chunkSize, opts.Progress)
+	if err != nil {
+		os.Remove(partial)
+		return err
+	}
+
+	return os.Rename(partial, path)
+}
+
+//downloadChunksTo writes size bytes of url to partial with workers fetching
+//chunkSize ranges at once
+func (i *Invoker) downloadChunksTo(ctx context.Context, url string, size int64, partial string, workers int, chunkSize int64, progress func(written, total int64)) error {
+	file, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = file.Truncate(size)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunks := make(chan byteRange)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		written  int64
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				n, err := i.downloadChunk(ctx, url, chunk, file)
+
+				mu.Lock()
+				written += n
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				if err == nil && progress != nil {
+					progress(written, size)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for start := int64(0); start < size; start += chunkSize {
+		end := start + chunkSize - 1
+		if end >= size {
+			end = size - 1
+		}
+
+		select {
+		case chunks <- byteRange{start: start, end: end}:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(chunks)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+//probeRanges returns the size of url and if the server accepts byte ranges,
+//its wait on the rate limiter covers the range requests which follow
+func (i *Invoker) probeRanges(ctx context.Context, url string) (int64, bool, error) {
+	req, err := i.newDownloadRequest(ctx, "HEAD", url)
+	if err != nil {
+		return 0, false, err
+	}
+
+	err = i.requestLimiter(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	res, err := i.client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("probing %s returned %s", url, res.Status)
+	}
+
+	return res.ContentLength, res.Header.Get("Accept-Ranges") == "bytes", nil
+}
+
+//downloadChunk writes one byte range of url into file at its offset
+func (i *Invoker) downloadChunk(ctx context.Context, url string, chunk byteRange, file io.WriterAt) (int64, error) {
+	req, err := i.newDownloadRequest(ctx, "GET", url)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", chunk.start, chunk.end))
+
+	res, err := i.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("range request for %s returned %s", url, res.Status)
+	}
+
+	start, err := contentRangeStart(res.Header.Get("Content-Range"))
+	if err != nil {
+		return 0, err
+	}
+	if start != chunk.start {
+		return 0, fmt.Errorf("server returned range at %d expected %d", start, chunk.start)
+	}
+
+	expected := chunk.end - chunk.start + 1
+	n, err := io.Copy(&offsetWriter{w: file, offset: chunk.start}, io.LimitReader(res.Body, expected))
+	if err != nil {
+		return n, err
+	}
+	if n != expected {
+		return n, fmt.Errorf("short range got %d bytes expected %d", n, expected)
+	}
+
+	return n, nil
+}
+
+//offsetWriter writes sequentially into a WriterAt from offset
+type offsetWriter struct {
+	w      io.WriterAt
+	offset int64
+}
+
+func (o *offsetWriter) Write(b []byte) (int, error) {
+	n, err := o.w.WriteAt(b, o.offset)
+	o.offset += int64(n)
+
+	return n, err
+}
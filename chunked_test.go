@@ -0,0 +1,171 @@
+package gbomb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestDownloadVideoChunked(t *testing.T) {
+	invoker := createTestInvoker()
+	client := &RangeMock{
+		content:      strings.Repeat("0123456789", 1001),
+		acceptRanges: true,
+	}
+	invoker.client = client
+	//only the probe may wait on the limiter
+	invoker.Limter = rate.NewLimiter(rate.Every(time.Hour), 1)
+
+	video := &VideoInfo{GUID: "2300-16713", HDURL: "https://giantbomb.com/video_hd.mp4"}
+	path := filepath.Join(t.TempDir(), "video.mp4")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var lastWritten int64
+	err := invoker.DownloadVideoChunked(ctx, video, QualityHD, path, &ChunkedDownloadOptions{
+		Workers:   3,
+		ChunkSize: 1000,
+		Progress: func(written, total int64) {
+			lastWritten = written
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.ranges) != 11 {
+		t.Errorf("made %d range requests expected %d", len(client.ranges), 11)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != client.content {
+		t.Errorf("reassembled content does not match")
+	}
+
+	if lastWritten != int64(len(client.content)) {
+		t.Errorf("invalid progress %d expected %d", lastWritten, len(client.content))
+	}
+}
+
+func TestDownloadVideoChunkedFailure(t *testing.T) {
+	invoker := createTestInvoker()
+	client := &RangeMock{
+		content:      strings.Repeat("0123456789", 1001),
+		acceptRanges: true,
+		failAfter:    500,
+	}
+	invoker.client = client
+
+	video := &VideoInfo{GUID: "2300-16713", HDURL: "https://giantbomb.com/video_hd.mp4"}
+	path := filepath.Join(t.TempDir(), "video.mp4")
+
+	opts := &ChunkedDownloadOptions{Workers: 1, ChunkSize: 1000}
+	err := invoker.DownloadVideoChunked(context.Background(), video, QualityHD, path, opts)
+	if err == nil {
+		t.Fatal("interrupted download did not fail")
+	}
+	if _, err := os.Stat(path + ChunksSuffix); !os.IsNotExist(err) {
+		t.Errorf("partial file was left behind")
+	}
+
+	err = invoker.DownloadVideoTo(context.Background(), video, QualityHD, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != client.content {
+		t.Errorf("downloaded content does not match")
+	}
+}
+
+func TestDownloadVideoChunkedLeftover(t *testing.T) {
+	invoker := createTestInvoker()
+	client := &RangeMock{
+		content:      strings.Repeat("0123456789", 1001),
+		acceptRanges: true,
+	}
+	invoker.client = client
+
+	video := &VideoInfo{GUID: "2300-16713", HDURL: "https://giantbomb.com/video_hd.mp4"}
+	path := filepath.Join(t.TempDir(), "video.mp4")
+
+	//a killed chunked download leaves a full length file with holes
+	err := ioutil.WriteFile(path+ChunksSuffix, make([]byte, len(client.content)), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = invoker.DownloadVideoTo(context.Background(), video, QualityHD, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != client.content {
+		t.Errorf("leftover chunks resumed as a partial download")
+	}
+
+	//while in progress the chunks must not be where DownloadVideoTo resumes from
+	os.Remove(path)
+	resumable := false
+	opts := &ChunkedDownloadOptions{Workers: 2, ChunkSize: 1000, Progress: func(written, total int64) {
+		if _, err := os.Stat(path + PartialSuffix); err == nil {
+			resumable = true
+		}
+	}}
+	err = invoker.DownloadVideoChunked(context.Background(), video, QualityHD, path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumable {
+		t.Errorf("chunked download written to %s", PartialSuffix)
+	}
+	data, err = ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != client.content {
+		t.Errorf("chunked download over leftover chunks does not match")
+	}
+}
+
+func TestDownloadVideoChunkedFallback(t *testing.T) {
+	invoker := createTestInvoker()
+	client := &RangeMock{content: strings.Repeat("giant bomb ", 500)}
+	invoker.client = client
+
+	video := &VideoInfo{GUID: "2300-16713", HighURL: "https://giantbomb.com/video_high.mp4"}
+	path := filepath.Join(t.TempDir(), "video.mp4")
+
+	err := invoker.DownloadVideoChunked(context.Background(), video, QualityHigh, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.ranges) != 1 || client.ranges[0] != "" {
+		t.Errorf("fallback made range requests %v", client.ranges)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != client.content {
+		t.Errorf("downloaded content does not match")
+	}
+}
@@ -0,0 +1,144 @@
+//Command gbomb works with the giant bomb API from the command line
+//
+//	gbomb sync -dir archive -show 3 -policy prefer-hd
+//	gbomb serve -addr :8080 -base-url http://feeds.example.com
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/sardap/gbomb"
+	"github.com/sardap/gbomb/proxy"
+)
+
+const usage = `usage: gbomb <command> [flags]
+
+commands:
+  sync   download every video of a show or category not yet in a local archive
+  serve  serve podcast and video feeds without exposing the API key
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "sync":
+		err = runSync(os.Args[2:])
+	case "serve":
+		err = runServe(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gbomb:", err)
+		os.Exit(1)
+	}
+}
+
+func runSync(args []string) error {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	endpoint := flags.String("endpoint", gbomb.DefaultSiteURL, "giant bomb API endpoint")
+	key := flags.String("key", os.Getenv("GIANTBOMB_API_KEY"), "API key, defaults to $GIANTBOMB_API_KEY")
+	dir := flags.String("dir", ".", "archive directory")
+	show := flags.Int("show", 0, "only sync videos from this video show ID")
+	category := flags.Int("category", 0, "only sync videos from this video category ID")
+	policy := flags.String("policy", gbomb.PreferHD.String(), "quality policy: prefer-hd, cap-at-high or prefer-smallest")
+	template := flags.String("template", gbomb.DefaultSyncTemplate, "file name template relative to dir")
+	flags.Parse(args)
+
+	if *key == "" {
+		return fmt.Errorf("no API key set use -key or $GIANTBOMB_API_KEY")
+	}
+	if *show == 0 && *category == 0 {
+		return fmt.Errorf("set -show or -category")
+	}
+
+	qualityPolicy, err := gbomb.ParseQualityPolicy(*policy)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		stop()
+	}()
+
+	invoker := gbomb.CreateInvoker(*endpoint, *key)
+	sync, err := gbomb.OpenVideoSync(invoker, *dir, gbomb.VideoFilter{Show: *show, Category: *category})
+	if err != nil {
+		return err
+	}
+	sync.Policy = qualityPolicy
+	sync.Template = *template
+	sync.OnVideo = func(video *gbomb.VideoInfo, path string, err error) {
+		if err != nil {
+			fmt.Printf("skipped %s %s: %v\n", video.GUID, video.Name, err)
+			return
+		}
+		fmt.Printf("downloaded %s %s\n", video.GUID, path)
+	}
+
+	stats, err := sync.Run(ctx)
+	fmt.Printf(
+		"%d downloaded %d already archived %d unavailable\n",
+		stats.Downloaded, stats.Existing, stats.Unavailable,
+	)
+
+	return err
+}
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	endpoint := flags.String("endpoint", gbomb.DefaultSiteURL, "giant bomb API endpoint")
+	key := flags.String("key", os.Getenv("GIANTBOMB_API_KEY"), "API key, defaults to $GIANTBOMB_API_KEY")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	baseURL := flags.String("base-url", "", "public URL of the server, defaults to http://localhost plus -addr")
+	cacheTTL := flags.Duration("cache", proxy.DefaultCacheTTL, "how long upstream feeds are reused")
+	policy := flags.String("policy", gbomb.PreferHD.String(), "quality policy: prefer-hd, cap-at-high or prefer-smallest")
+	secret := flags.String(
+		"secret", os.Getenv("GBOMB_PROXY_SECRET"),
+		"signs download links so they survive restarts, random if unset, defaults to $GBOMB_PROXY_SECRET",
+	)
+	flags.Parse(args)
+
+	if *key == "" {
+		return fmt.Errorf("no API key set use -key or $GIANTBOMB_API_KEY")
+	}
+
+	qualityPolicy, err := gbomb.ParseQualityPolicy(*policy)
+	if err != nil {
+		return err
+	}
+
+	if *baseURL == "" {
+		*baseURL = "http://localhost" + *addr
+	}
+
+	server := proxy.New(gbomb.CreateInvoker(*endpoint, *key), *baseURL)
+	server.CacheTTL = *cacheTTL
+	server.Policy = qualityPolicy
+	if *secret != "" {
+		server.Secret = []byte(*secret)
+	}
+
+	fmt.Printf("serving feeds at %s\n", *baseURL)
+	httpServer := &http.Server{Addr: *addr, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+
+	return httpServer.ListenAndServe()
+}
@@ -0,0 +1,275 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+//Relation a Game relationship slice the crawler can follow
+type Relation string
+
+//Relations on Game that hold CompleteTag slices
+const (
+	RelationSimilarGames              Relation = "similar_games"
+	RelationFranchises                Relation = "franchises"
+	RelationDevelopers                Relation = "developers"
+	RelationPublishers                Relation = "publishers"
+	RelationCharacters                Relation = "characters"
+	RelationConcepts                  Relation = "concepts"
+	RelationLocations                 Relation = "locations"
+	RelationObjects                   Relation = "objects"
+	RelationPeople                    Relation = "people"
+	RelationGenres                    Relation = "genres"
+	RelationThemes                    Relation = "themes"
+	RelationDLCS                      Relation = "dlcs"
+	RelationReleases                  Relation = "releases"
+	RelationFirstAppearanceCharacters Relation = "first_appearance_characters"
+	RelationFirstAppearanceConcepts   Relation = "first_appearance_concepts"
+	RelationFirstAppearanceLocations  Relation = "first_appearance_locations"
+	RelationFirstAppearanceObjects    Relation = "first_appearance_objects"
+	RelationFirstAppearancePeople     Relation = "first_appearance_people"
+	RelationKilledCharacters          Relation = "killed_characters"
+)
+
+//Tags returns the tags on a game for a relation
+func (g *Game) Tags(relation Relation) []CompleteTag {
+	switch relation {
+	case RelationSimilarGames:
+		return g.SimilarGames
+	case RelationFranchises:
+		return g.Franchises
+	case RelationDevelopers:
+		return g.Developers
+	case RelationPublishers:
+		return g.Publishers
+	case RelationCharacters:
+		return g.Characters
+	case RelationConcepts:
+		return g.Concepts
+	case RelationLocations:
+		return g.Locations
+	case RelationObjects:
+		return g.Objects
+	case RelationPeople:
+		return g.Persons
+	case RelationGenres:
+		return g.Genres
+	case RelationThemes:
+		return g.Themes
+	case RelationDLCS:
+		return g.DLCS
+	case RelationReleases:
+		return g.Releases
+	case RelationFirstAppearanceCharacters:
+		return g.FirstAppearanceCharacters
+	case RelationFirstAppearanceConcepts:
+		return g.FirstAppearanceConcepts
+	case RelationFirstAppearanceLocations:
+		return g.FirstAppearanceLocations
+	case RelationFirstAppearanceObjects:
+		return g.FirstAppearanceObjects
+	case RelationFirstAppearancePeople:
+		return g.FirstAppearancePeople
+	case RelationKilledCharacters:
+		return g.KilledCharacters
+	}
+
+	return nil
+}
+
+//CrawlNode a resource visited by the crawler
+type CrawlNode struct {
+	GUID  GUID   `json:"guid"`
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+	//Resource is the value returned by Resolve it is not checkpointed
+	Resource interface{} `json:"-"`
+}
+
+//CrawlEdge a relationship between two crawled resources
+type CrawlEdge struct {
+	From     GUID     `json:"from"`
+	To       GUID     `json:"to"`
+	Relation Relation `json:"relation"`
+}
+
+//CrawlState the graph built so far and what is left to visit
+type CrawlState struct {
+	Nodes   map[GUID]*CrawlNode `json:"nodes"`
+	Edges   []CrawlEdge         `json:"edges"`
+	Pending []CrawlNode         `json:"pending"`
+}
+
+//Crawler walks related giant bomb entities starting from a set of GUIDs
+type Crawler struct {
+	Invoker *Invoker
+	//MaxDepth how many relationship hops to follow from the start GUIDs
+	MaxDepth int
+	//Relations to follow on games all relations are followed if empty
+	Relations []Relation
+	//CheckpointPath if set the state is written here after every visit
+	CheckpointPath string
+	//OnVisit called after every resource is fetched
+	OnVisit func(node *CrawlNode)
+	State   CrawlState
+}
+
+//CreateCrawler Creates a crawler seeded with the given GUIDs
+func CreateCrawler(invoker *Invoker, maxDepth int, start ...GUID) *Crawler {
+	c := &Crawler{
+		Invoker:  invoker,
+		MaxDepth: maxDepth,
+		State: CrawlState{
+			Nodes: make(map[GUID]*CrawlNode),
+		},
+	}
+	for _, guid := range start {
+		c.State.Pending = append(c.State.Pending, CrawlNode{GUID: guid})
+	}
+
+	return c
+}
+
+//ResumeCrawler Creates a crawler from a checkpoint file
+func ResumeCrawler(invoker *Invoker, maxDepth int, checkpointPath string) (*Crawler, error) {
+	data, err := ioutil.ReadFile(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+
+	c := CreateCrawler(invoker, maxDepth)
+	c.CheckpointPath = checkpointPath
+	err = json.Unmarshal(data, &c.State)
+	if err != nil {
+		return nil, err
+	}
+	if c.State.Nodes == nil {
+		c.State.Nodes = make(map[GUID]*CrawlNode)
+	}
+
+	return c, nil
+}
+
+//Checkpoint writes the crawl state to CheckpointPath
+func (c *Crawler) Checkpoint() error {
+	if c.CheckpointPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c.State)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.CheckpointPath + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, c.CheckpointPath)
+}
+
+func (c *Crawler) relations() []Relation {
+	if len(c.Relations) > 0 {
+		return c.Relations
+	}
+
+	return []Relation{
+		RelationSimilarGames, RelationFranchises, RelationDevelopers,
+		RelationPublishers, RelationCharacters, RelationConcepts,
+		RelationLocations, RelationObjects, RelationPeople, RelationGenres,
+		RelationThemes, RelationDLCS, RelationReleases,
+		RelationFirstAppearanceCharacters, RelationFirstAppearanceConcepts,
+		RelationFirstAppearanceLocations, RelationFirstAppearanceObjects,
+		RelationFirstAppearancePeople, RelationKilledCharacters,
+	}
+}
+
+func (c *Crawler) queued(guid GUID) bool {
+	for _, node := range c.State.Pending {
+		if node.GUID == guid {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Step visits the next pending resource returning false when nothing is left
+func (c *Crawler) Step(ctx context.Context) (bool, error) {
+	if len(c.State.Pending) == 0 {
+		return false, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	node := c.State.Pending[0]
+	if _, ok := c.State.Nodes[node.GUID]; ok {
+		c.State.Pending = c.State.Pending[1:]
+		return true, nil
+	}
+
+	resource, err := c.Invoker.Resolve(ctx, node.GUID)
+	if err != nil {
+		return false, err
+	}
+	c.State.Pending = c.State.Pending[1:]
+
+	node.Resource = resource
+	switch r := resource.(type) {
+	case *Game:
+		node.Name = r.Name
+		for _, relation := range c.relations() {
+			for _, tag := range r.Tags(relation) {
+				to, err := GUIDFromDetailURL(tag.APIDetailURL)
+				if err != nil {
+					continue
+				}
+
+				c.State.Edges = append(c.State.Edges, CrawlEdge{
+					From: node.GUID, To: to, Relation: relation,
+				})
+
+				if node.Depth >= c.MaxDepth {
+					continue
+				}
+				if _, ok := c.State.Nodes[to]; ok || c.queued(to) {
+					continue
+				}
+				c.State.Pending = append(c.State.Pending, CrawlNode{
+					GUID: to, Name: tag.Name, Depth: node.Depth + 1,
+				})
+			}
+		}
+	case *Resource:
+		node.Name = r.Name
+	}
+	c.State.Nodes[node.GUID] = &node
+
+	if c.OnVisit != nil {
+		c.OnVisit(&node)
+	}
+
+	if err := c.Checkpoint(); err != nil {
+		return false, err
+	}
+
+	return len(c.State.Pending) > 0, nil
+}
+
+//Run visits every pending resource until MaxDepth is reached
+func (c *Crawler) Run(ctx context.Context) error {
+	for {
+		more, err := c.Step(ctx)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+}
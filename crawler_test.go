@@ -0,0 +1,99 @@
+package gbomb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func createCrawlMock() *RouteMock {
+	return &RouteMock{
+		routes: map[string]string{
+			"https://www.giantbomb.com/api/game/3030-56733?api_key=coolbeans&format=json&offset=0":    "test_data/gameRequest.json",
+			"https://www.giantbomb.com/api/game/3030-14623?api_key=coolbeans&format=json&offset=0":    "test_data/gameRequest.json",
+			"https://www.giantbomb.com/api/game/3030-2931?api_key=coolbeans&format=json&offset=0":     "test_data/gameRequest.json",
+			"https://www.giantbomb.com/api/franchise/3025-1?api_key=coolbeans&format=json&offset=0":   "test_data/franchise.json",
+			"https://www.giantbomb.com/api/franchise/3025-643?api_key=coolbeans&format=json&offset=0": "test_data/franchise.json",
+		},
+	}
+}
+
+func TestCrawler(t *testing.T) {
+	invoker := createTestInvoker()
+	client := createCrawlMock()
+	invoker.client = client
+
+	crawler := CreateCrawler(invoker, 1, "3030-56733")
+	crawler.Relations = []Relation{RelationSimilarGames, RelationFranchises}
+	err := crawler.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(crawler.State.Nodes) != 5 {
+		t.Errorf(
+			"invalid number of nodes crawled %d expected %d",
+			len(crawler.State.Nodes), 5,
+		)
+	}
+
+	for url, hits := range client.hits {
+		if hits != 1 {
+			t.Errorf("%s requested %d times", url, hits)
+		}
+	}
+
+	if crawler.State.Nodes["3025-643"].Depth != 1 {
+		t.Errorf(
+			"invalid depth %d expected %d",
+			crawler.State.Nodes["3025-643"].Depth, 1,
+		)
+	}
+}
+
+func TestCrawlerResume(t *testing.T) {
+	checkpoint := filepath.Join(t.TempDir(), "crawl.json")
+
+	invoker := createTestInvoker()
+	invoker.client = createCrawlMock()
+
+	crawler := CreateCrawler(invoker, 1, "3030-56733")
+	crawler.Relations = []Relation{RelationSimilarGames, RelationFranchises}
+	crawler.CheckpointPath = checkpoint
+	for i := 0; i < 2; i++ {
+		if _, err := crawler.Step(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := createCrawlMock()
+	invoker.client = client
+	resumed, err := ResumeCrawler(invoker, 1, checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed.Relations = crawler.Relations
+
+	if len(resumed.State.Nodes) != 2 || len(resumed.State.Pending) != 3 {
+		t.Fatalf(
+			"invalid resumed state %d nodes %d pending",
+			len(resumed.State.Nodes), len(resumed.State.Pending),
+		)
+	}
+
+	err = resumed.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resumed.State.Nodes) != 5 {
+		t.Errorf(
+			"invalid number of nodes crawled %d expected %d",
+			len(resumed.State.Nodes), 5,
+		)
+	}
+
+	if len(client.hits) != 3 {
+		t.Errorf("resumed crawl made %d requests expected %d", len(client.hits), 3)
+	}
+}
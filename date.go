@@ -0,0 +1,166 @@
+package gbomb
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	//embeds the tz database for hosts without one e.g. scratch containers
+	_ "time/tzdata"
+)
+
+//Layouts giant bomb uses for dates
+const (
+	DateTimeLayout = "2006-01-02 15:04:05"
+	DateLayout     = "2006-01-02"
+)
+
+//ErrNoDate returned when converting a null or empty Date
+var ErrNoDate = fmt.Errorf("date is not set")
+
+//PacificTime the timezone giant bomb dates are in including daylight saving
+var PacificTime = loadPacificTime()
+
+func loadPacificTime() *time.Location {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		//unreachable as time/tzdata is embedded
+		panic(err)
+	}
+
+	return loc
+}
+
+//Date a giant bomb time
+type Date struct {
+	date string
+}
+
+//NewDate creates a Date from a time converting it to giant bomb's timezone
+func NewDate(t time.Time) Date {
+	if t.IsZero() {
+		return Date{}
+	}
+
+	return Date{date: t.In(PacificTime).Format(DateTimeLayout)}
+}
+
+//ParseDate parses a giant bomb date in either datetime or date only layouts
+func ParseDate(s string) (Date, error) {
+	d := Date{date: s}
+	if s == "" {
+		return d, nil
+	}
+
+	if _, err := d.GetTime(); err != nil {
+		return Date{}, err
+	}
+
+	return d, nil
+}
+
+func (d Date) layout() (string, error) {
+	switch len(d.date) {
+	case len(DateTimeLayout):
+		return DateTimeLayout, nil
+	case len(DateLayout):
+		return DateLayout, nil
+	}
+
+	return "", fmt.Errorf("invalid giant bomb date %q", d.date)
+}
+
+//UnmarshalJSON custom json unmarshaler
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		d.date = ""
+		return nil
+	}
+
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := ParseDate(str)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+//MarshalJSON custom json marshaler writes null for unset dates
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(d.date)
+}
+
+//String returns date as string
+func (d Date) String() string {
+	return d.date
+}
+
+//IsZero returns if the date is null or empty
+func (d Date) IsZero() bool {
+	return d.date == ""
+}
+
+//HasTime returns if the date includes a time of day
+func (d Date) HasTime() bool {
+	return len(d.date) == len(DateTimeLayout)
+}
+
+//GetTime converts from giant bomb date to time.Time in PacificTime
+func (d Date) GetTime() (time.Time, error) {
+	if d.IsZero() {
+		return time.Time{}, ErrNoDate
+	}
+
+	layout, err := d.layout()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.ParseInLocation(layout, d.date, PacificTime)
+}
+
+//Scan implements sql.Scanner
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		d.date = ""
+		return nil
+	case time.Time:
+		*d = NewDate(v)
+		return nil
+	case string:
+		parsed, err := ParseDate(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case []byte:
+		return d.Scan(string(v))
+	}
+
+	return fmt.Errorf("cannot scan %T into Date", value)
+}
+
+//Value implements driver.Valuer
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+
+	return d.date, nil
+}
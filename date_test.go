@@ -0,0 +1,102 @@
+package gbomb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateUnmarshal(t *testing.T) {
+	var tmp struct {
+		DateTime Date `json:"date_time"`
+		DateOnly Date `json:"date_only"`
+		Null     Date `json:"null"`
+	}
+	err := json.Unmarshal(
+		[]byte(`{"date_time": "2016-10-20 11:11:30", "date_only": "2017-10-27", "null": null}`),
+		&tmp,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tme, err := tmp.DateTime.GetTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := time.Date(2016, 10, 20, 18, 11, 30, 0, time.UTC)
+	if !tme.Equal(expected) {
+		t.Errorf("invalid time %s expected %s", tme, expected)
+	}
+
+	tme, err = tmp.DateOnly.GetTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tme.Year() != 2017 || tme.Month() != time.October || tme.Day() != 27 {
+		t.Errorf("invalid date %s expected 2017-10-27", tme)
+	}
+	if tmp.DateOnly.HasTime() {
+		t.Errorf("date only has time")
+	}
+
+	if !tmp.Null.IsZero() {
+		t.Errorf("null date was %q", tmp.Null.String())
+	}
+	if _, err := tmp.Null.GetTime(); err != ErrNoDate {
+		t.Errorf("null date returned %v expected %v", err, ErrNoDate)
+	}
+
+	var bad Date
+	if err := json.Unmarshal([]byte(`"27th October"`), &bad); err == nil {
+		t.Errorf("parsed invalid date")
+	}
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	for _, str := range []string{
+		`"2016-10-20 11:11:30"`, `"2017-10-27"`, `null`,
+	} {
+		var d Date
+		if err := json.Unmarshal([]byte(str), &d); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != str {
+			t.Errorf("round trip produced %s expected %s", data, str)
+		}
+	}
+}
+
+func TestDateSQL(t *testing.T) {
+	d, err := ParseDate("2017-10-27")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := d.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scanned Date
+	if err := scanned.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+	if scanned != d {
+		t.Errorf("scanned %s expected %s", scanned, d)
+	}
+
+	if err := scanned.Scan(nil); err != nil || !scanned.IsZero() {
+		t.Errorf("scanning nil gave %s %v", scanned, err)
+	}
+
+	if value, _ := scanned.Value(); value != nil {
+		t.Errorf("zero date value was %v", value)
+	}
+}
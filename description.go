@@ -0,0 +1,402 @@
+package gbomb
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const noCaption = "No Caption Provided"
+
+var cellSpace = strings.NewReplacer(" \t", "\t", "\t ", "\t")
+
+//DescriptionImage an image embedded in a description with a <figure>
+type DescriptionImage struct {
+	URL     string
+	RefID   GUID
+	Caption string
+	Width   int
+}
+
+//DescriptionRef a cross link to another giant bomb resource
+type DescriptionRef struct {
+	GUID GUID
+	Text string
+	//Path the site path the link points to e.g. /nintendo-epd/3010-11421/
+	Path string
+}
+
+//DescriptionSection the content under a heading, the section before the first
+//heading has an empty Heading
+type DescriptionSection struct {
+	Heading string
+	Level   int
+	Text    string
+	Images  []DescriptionImage
+	Refs    []DescriptionRef
+}
+
+//Description a parsed giant bomb wiki description
+type Description struct {
+	Sections []DescriptionSection
+}
+
+//ParseDescription parses giant bomb wiki HTML into sections
+func ParseDescription(html string) (*Description, error) {
+	p := &descriptionParser{
+		desc:    &Description{},
+		section: &DescriptionSection{},
+	}
+
+	err := walkDescription(html, p)
+	if err != nil {
+		return nil, err
+	}
+	p.flush()
+
+	return p.desc, nil
+}
+
+//ParseDescription parses the game's description
+func (g *Game) ParseDescription() (*Description, error) {
+	return ParseDescription(g.Description)
+}
+
+//Section returns the first section with a heading matching ignoring case
+func (d *Description) Section(heading string) (DescriptionSection, bool) {
+	for _, section := range d.Sections {
+		if strings.EqualFold(section.Heading, heading) {
+			return section, true
+		}
+	}
+
+	return DescriptionSection{}, false
+}
+
+//Text returns the whole description as plain text with headings on their own
+//lines
+func (d *Description) Text() string {
+	var parts []string
+	for _, section := range d.Sections {
+		if section.Heading != "" {
+			parts = append(parts, section.Heading)
+		}
+		if section.Text != "" {
+			parts = append(parts, section.Text)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+//Images returns every embedded image in order
+func (d *Description) Images() []DescriptionImage {
+	var images []DescriptionImage
+	for _, section := range d.Sections {
+		images = append(images, section.Images...)
+	}
+
+	return images
+}
+
+//Refs returns every cross link in order
+func (d *Description) Refs() []DescriptionRef {
+	var refs []DescriptionRef
+	for _, section := range d.Sections {
+		refs = append(refs, section.Refs...)
+	}
+
+	return refs
+}
+
+//RefGUIDs returns the unique GUIDs linked from the description in order of
+//first appearance
+func (d *Description) RefGUIDs() []GUID {
+	seen := make(map[GUID]bool)
+	var guids []GUID
+	for _, ref := range d.Refs() {
+		if seen[ref.GUID] {
+			continue
+		}
+		seen[ref.GUID] = true
+		guids = append(guids, ref.GUID)
+	}
+
+	return guids
+}
+
+//descriptionHandler receives the elements of a description from
+//walkDescription, names are lower case
+type descriptionHandler interface {
+	start(name string, t xml.StartElement)
+	end(name string)
+	text(s string)
+	//image is called when a <figure> closes, nothing inside it is passed to
+	//start, end or text
+	image(img DescriptionImage)
+}
+
+//walkDescription tokenizes giant bomb wiki HTML passing it to h, <noscript>
+//content is dropped and figures are collected into images
+func walkDescription(html string, h descriptionHandler) error {
+	decoder := xml.NewDecoder(strings.NewReader(html))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var figure *DescriptionImage
+	noscript := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if noscript > 0 || name == "noscript" {
+				noscript++
+				continue
+			}
+
+			if figure != nil {
+				if name == "img" {
+					if figure.URL == "" {
+						figure.URL = attr(t, "src")
+					}
+					if alt := attr(t, "alt"); alt != "" && alt != noCaption && figure.Caption == "" {
+						figure.Caption = alt
+					}
+				}
+				continue
+			}
+
+			if name == "figure" {
+				width, _ := strconv.Atoi(attr(t, "data-width"))
+				figure = &DescriptionImage{
+					URL:   attr(t, "data-img-src"),
+					RefID: GUID(attr(t, "data-ref-id")),
+					Width: width,
+				}
+				continue
+			}
+			h.start(name, t)
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			if noscript > 0 {
+				noscript--
+				continue
+			}
+
+			if figure != nil {
+				if name == "figure" {
+					h.image(*figure)
+					figure = nil
+				}
+				continue
+			}
+			h.end(name)
+		case xml.CharData:
+			if noscript > 0 {
+				continue
+			}
+
+			if figure != nil {
+				if caption := strings.TrimSpace(string(t)); caption != "" {
+					figure.Caption = collapseSpace(figure.Caption + " " + caption)
+				}
+				continue
+			}
+			h.text(string(t))
+		}
+	}
+}
+
+//descriptionRef returns the cross link an <a> points to if it has a valid
+//data-ref-id
+func descriptionRef(t xml.StartElement) (DescriptionRef, bool) {
+	refID := GUID(attr(t, "data-ref-id"))
+	if refID == "" || refID.Validate() != nil {
+		return DescriptionRef{}, false
+	}
+
+	return DescriptionRef{GUID: refID, Path: attr(t, "href")}, true
+}
+
+type descriptionParser struct {
+	desc    *Description
+	section *DescriptionSection
+	//blocks finished paragraphs of the current section
+	blocks []string
+	line   strings.Builder
+
+	heading   *strings.Builder
+	ref       *DescriptionRef
+	refText   strings.Builder
+	table     int
+	cellCount int
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+func headingLevel(name string) int {
+	if len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6' {
+		return int(name[1] - '0')
+	}
+
+	return 0
+}
+
+func (p *descriptionParser) start(name string, t xml.StartElement) {
+	if level := headingLevel(name); level > 0 {
+		p.flush()
+		p.section = &DescriptionSection{Level: level}
+		p.heading = &strings.Builder{}
+		return
+	}
+
+	switch name {
+	case "table":
+		p.breakBlock()
+		p.table++
+	case "p", "ul", "ol", "blockquote":
+		p.breakBlockOutsideTable()
+	case "tr":
+		p.breakLine()
+		p.cellCount = 0
+	case "li", "br":
+		if p.table > 0 {
+			p.write(" ")
+			break
+		}
+		p.breakLine()
+	case "td", "th":
+		if p.cellCount > 0 {
+			p.write("\t")
+		}
+		p.cellCount++
+	case "a":
+		if ref, ok := descriptionRef(t); ok {
+			p.ref = &ref
+			p.refText.Reset()
+		}
+	}
+}
+
+func (p *descriptionParser) end(name string) {
+	if headingLevel(name) > 0 && p.heading != nil {
+		p.section.Heading = collapseSpace(p.heading.String())
+		p.heading = nil
+		return
+	}
+
+	switch name {
+	case "table":
+		p.breakBlock()
+		if p.table > 0 {
+			p.table--
+		}
+	case "p", "ul", "ol", "blockquote":
+		p.breakBlockOutsideTable()
+	case "tr":
+		p.breakLine()
+	case "li":
+		if p.table == 0 {
+			p.breakLine()
+		}
+	case "a":
+		if p.ref != nil {
+			p.ref.Text = collapseSpace(p.refText.String())
+			p.section.Refs = append(p.section.Refs, *p.ref)
+			p.ref = nil
+		}
+	}
+}
+
+func (p *descriptionParser) text(s string) {
+	if p.heading != nil {
+		p.heading.WriteString(s)
+		return
+	}
+
+	if p.ref != nil {
+		p.refText.WriteString(s)
+	}
+	p.write(s)
+}
+
+func (p *descriptionParser) image(img DescriptionImage) {
+	p.breakBlockOutsideTable()
+	p.section.Images = append(p.section.Images, img)
+}
+
+func (p *descriptionParser) write(s string) {
+	p.line.WriteString(s)
+}
+
+//breakLine ends the current line within a block
+func (p *descriptionParser) breakLine() {
+	line := strings.TrimSpace(cellSpace.Replace(collapseSpace(p.line.String())))
+	p.line.Reset()
+	if line == "" {
+		return
+	}
+
+	if len(p.blocks) > 0 && !strings.HasSuffix(p.blocks[len(p.blocks)-1], "\n\n") {
+		p.blocks[len(p.blocks)-1] += "\n" + line
+		return
+	}
+	p.blocks = append(p.blocks, line)
+}
+
+//breakBlock ends the current paragraph
+func (p *descriptionParser) breakBlock() {
+	p.breakLine()
+	if len(p.blocks) > 0 && !strings.HasSuffix(p.blocks[len(p.blocks)-1], "\n\n") {
+		p.blocks[len(p.blocks)-1] += "\n\n"
+	}
+}
+
+//breakBlockOutsideTable ends the paragraph unless inside a table where
+//paragraphs in cells are joined with a space to keep one row per line
+func (p *descriptionParser) breakBlockOutsideTable() {
+	if p.table > 0 {
+		p.write(" ")
+		return
+	}
+
+	p.breakBlock()
+}
+
+//flush finishes the current section
+func (p *descriptionParser) flush() {
+	p.breakBlock()
+
+	text := strings.TrimSpace(strings.Join(p.blocks, ""))
+	p.section.Text = text
+	p.blocks = nil
+
+	if p.section.Heading != "" || text != "" || len(p.section.Images) > 0 || len(p.section.Refs) > 0 {
+		p.desc.Sections = append(p.desc.Sections, *p.section)
+	}
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return r != '\t' && unicode.IsSpace(r)
+	}), " ")
+}
@@ -0,0 +1,93 @@
+package gbomb
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseDescription(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	desc, err := game.ParseDescription()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headings := []string{
+		"Overview", "Announcement", "Captures", "Kingdoms", "Power Moons",
+		"Nintendo Labo VR-Kit Support",
+	}
+	if len(desc.Sections) != len(headings) {
+		t.Fatalf("invalid number of sections %d expected %d", len(desc.Sections), len(headings))
+	}
+	for i, heading := range headings {
+		if desc.Sections[i].Heading != heading {
+			t.Errorf("invalid heading %s expected %s", desc.Sections[i].Heading, heading)
+		}
+	}
+
+	overview, ok := desc.Section("overview")
+	if !ok {
+		t.Fatal("no overview section")
+	}
+	if !strings.HasPrefix(overview.Text, "Super Mario Odyssey is a 3D platformer developed by Nintendo EPD and") {
+		t.Errorf("invalid overview text %s", overview.Text)
+	}
+	if strings.Contains(desc.Text(), "<") {
+		t.Errorf("plain text contains HTML")
+	}
+
+	if overview.Refs[0].GUID != "3010-11421" || overview.Refs[0].Text != "Nintendo EPD" {
+		t.Errorf("invalid first ref %v", overview.Refs[0])
+	}
+
+	captures, _ := desc.Section("Captures")
+	if !strings.Contains(captures.Text, "\nFrog\tAllows Mario to jump higher\n") {
+		t.Errorf("invalid table text %s", captures.Text[:100])
+	}
+
+	images := desc.Images()
+	if images[0].RefID != "1300-3038048" || images[0].Width != 900 {
+		t.Errorf("invalid first image %v", images[0])
+	}
+
+	for _, guid := range desc.RefGUIDs() {
+		if guid.TypeID() == 1300 {
+			t.Errorf("image %s included in refs", guid)
+		}
+	}
+}
+
+func TestParseDescriptionFragment(t *testing.T) {
+	desc, err := ParseDescription(
+		`<p>Intro &amp; more<br>line two</p><h3>Notes</h3><ul><li>One</li><li>Two <a href="/mario/3005-177/" data-ref-id="3005-177">Mario</a></li></ul>` +
+			`<figure data-img-src="https://static.giantbomb.com/a.jpg" data-ref-id="1300-1"><img src="https://static.giantbomb.com/b.jpg" alt="No Caption Provided"><figcaption>A caption</figcaption></figure>`,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if desc.Sections[0].Heading != "" || desc.Sections[0].Text != "Intro & more\nline two" {
+		t.Errorf("invalid intro %q", desc.Sections[0].Text)
+	}
+
+	notes := desc.Sections[1]
+	if notes.Level != 3 || notes.Text != "One\nTwo Mario" {
+		t.Errorf("invalid notes %d %q", notes.Level, notes.Text)
+	}
+
+	if len(notes.Images) != 1 || notes.Images[0].Caption != "A caption" {
+		t.Errorf("invalid images %v", notes.Images)
+	}
+
+	guids := desc.RefGUIDs()
+	if len(guids) != 1 || guids[0] != "3005-177" {
+		t.Errorf("invalid refs %v", guids)
+	}
+}
@@ -0,0 +1,236 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//DownloadOptions options for DownloadVideoTo
+type DownloadOptions struct {
+	//Progress called as data is written with the bytes on disk and the total
+	//size, total is -1 when the server does not send a length
+	Progress func(written, total int64)
+
+	//apiHostOnly sends the API key and waits on the rate limiter only for
+	//requests to the API host as podcast requests do
+	apiHostOnly bool
+}
+
+//PartialSuffix appended to the destination path while a download is in progress
+const PartialSuffix = ".part"
+
+//newDownloadRequest creates a request carrying the API key
+func (i *Invoker) newDownloadRequest(ctx context.Context, method, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("api_key", i.APIKey)
+	req.URL.RawQuery = q.Encode()
+
+	return req, nil
+}
+
+//DownloadVideoTo downloads a video to path, data is written to path+".part"
+//and resumed from its size with a Range request if a previous attempt was
+//interrupted, the file is renamed to path once the full length is written
+func (i *Invoker) DownloadVideoTo(ctx context.Context, video *VideoInfo, quality Quality, path string, opts *DownloadOptions) error {
+	if !quality.Downloadable() {
+		return fmt.Errorf("%s is not a direct download", quality)
+	}
+
+	url := video.QualityURL(quality)
+	if url == "" {
+		return fmt.Errorf("video %s has no %s url", video.GUID, quality)
+	}
+
+	return i.downloadFileTo(ctx, url, path, opts)
+}
+
+func (i *Invoker) downloadFileTo(ctx context.Context, url, path string, opts *DownloadOptions) error {
+	partial := path + PartialSuffix
+	err := i.downloadPartial(ctx, url, partial, opts)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(partial, path)
+}
+
+//downloadPartial downloads url to partial resuming from the bytes already in
+//it, partial is complete once it returns without an error
+func (i *Invoker) downloadPartial(ctx context.Context, url, partial string, opts *DownloadOptions) error {
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+
+	file, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	offset := info.Size()
+
+	res, err := i.requestFrom(ctx, url, offset, opts)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		start, err := contentRangeStart(res.Header.Get("Content-Range"))
+		if err != nil {
+			return err
+		}
+		if start != offset {
+			return fmt.Errorf("server resumed at %d expected %d", start, offset)
+		}
+	case http.StatusOK:
+		//the server ignored the range so start again
+		offset = 0
+	case http.StatusRequestedRangeNotSatisfiable:
+		//the whole file is already in partial e.g. the rename was interrupted
+		if total, err := contentRangeTotal(res.Header.Get("Content-Range")); err == nil && total == offset {
+			if opts.Progress != nil {
+				opts.Progress(offset, total)
+			}
+
+			return file.Close()
+		}
+
+		return fmt.Errorf(
+			"server rejected resuming %s at %d remove %s to start again", url, offset, partial,
+		)
+	default:
+		return fmt.Errorf("downloading %s returned %s", url, res.Status)
+	}
+
+	err = file.Truncate(offset)
+	if err != nil {
+		return err
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	total := int64(-1)
+	if res.ContentLength >= 0 {
+		total = offset + res.ContentLength
+	}
+
+	w := &progressWriter{w: file, written: offset, total: total, progress: opts.Progress}
+	_, err = io.Copy(w, res.Body)
+	if err != nil {
+		return err
+	}
+
+	if total >= 0 && w.written != total {
+		return fmt.Errorf("short download got %d bytes expected %d", w.written, total)
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+//requestFrom requests url asking for the bytes from offset onwards
+func (i *Invoker) requestFrom(ctx context.Context, url string, offset int64, opts *DownloadOptions) (*http.Response, error) {
+	newRequest := i.newDownloadRequest
+	if opts.apiHostOnly {
+		newRequest = i.newPodcastRequest
+	}
+	req, err := newRequest(ctx, "GET", url)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	if !opts.apiHostOnly || i.isAPIHost(req.URL) {
+		err = i.requestLimiter(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return i.client.Do(req)
+}
+
+//OpenDownload makes a GET or HEAD request for url passing on a Range header
+//if one is given, requests to the API host carry the API key and wait for the
+//rate limiter while other hosts e.g. podcast CDNs are requested directly, the
+//caller closes the body
+func (i *Invoker) OpenDownload(ctx context.Context, method, url, byteRange string) (*http.Response, error) {
+	req, err := i.newPodcastRequest(ctx, method, url)
+	if err != nil {
+		return nil, err
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+
+	if i.isAPIHost(req.URL) {
+		err = i.requestLimiter(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return i.client.Do(req)
+}
+
+//contentRangeStart returns the first byte of a Content-Range header
+//e.g. bytes 100-199/200
+func contentRangeStart(header string) (int64, error) {
+	if !strings.HasPrefix(header, "bytes ") {
+		return 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(header, "bytes "), "-", 2)
+
+	return strconv.ParseInt(parts[0], 10, 64)
+}
+
+//contentRangeTotal returns the complete length of a Content-Range header
+//e.g. bytes */200
+func contentRangeTotal(header string) (int64, error) {
+	idx := strings.LastIndex(header, "/")
+	if !strings.HasPrefix(header, "bytes ") || idx < 0 {
+		return 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+
+	return strconv.ParseInt(header[idx+1:], 10, 64)
+}
+
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.progress != nil {
+		p.progress(p.written, p.total)
+	}
+
+	return n, err
+}
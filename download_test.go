@@ -0,0 +1,201 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//brokenReader returns an error after limit bytes like a dropped connection
+type brokenReader struct {
+	r     io.Reader
+	limit int
+}
+
+func (b *brokenReader) Read(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return 0, fmt.Errorf("connection reset")
+	}
+	if len(p) > b.limit {
+		p = p[:b.limit]
+	}
+	n, err := b.r.Read(p)
+	b.limit -= n
+
+	return n, err
+}
+
+//RangeMock serves content honouring Range headers
+type RangeMock struct {
+	mu           sync.Mutex
+	content      string
+	failAfter    int
+	acceptRanges bool
+	ranges       []string
+}
+
+func (r *RangeMock) Do(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req.URL.Query().Get("api_key") != "coolbeans" {
+		return nil, fmt.Errorf("missing api key %s", req.URL)
+	}
+
+	res := &http.Response{Header: make(http.Header)}
+	if r.acceptRanges {
+		res.Header.Set("Accept-Ranges", "bytes")
+	}
+
+	if req.Method == "HEAD" {
+		res.StatusCode = http.StatusOK
+		res.Status = http.StatusText(http.StatusOK)
+		res.ContentLength = int64(len(r.content))
+		res.Body = ioutil.NopCloser(strings.NewReader(""))
+		return res, nil
+	}
+
+	header := req.Header.Get("Range")
+	r.ranges = append(r.ranges, header)
+
+	start, end := 0, len(r.content)-1
+	status := http.StatusOK
+	if header != "" {
+		bounds := strings.Split(strings.TrimPrefix(header, "bytes="), "-")
+		start, _ = strconv.Atoi(bounds[0])
+		if start >= len(r.content) {
+			res.StatusCode = http.StatusRequestedRangeNotSatisfiable
+			res.Status = http.StatusText(res.StatusCode)
+			res.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", len(r.content)))
+			res.Body = ioutil.NopCloser(strings.NewReader(""))
+			return res, nil
+		}
+		if bounds[1] != "" {
+			end, _ = strconv.Atoi(bounds[1])
+		}
+		status = http.StatusPartialContent
+		res.Header.Set(
+			"Content-Range",
+			fmt.Sprintf("bytes %d-%d/%d", start, end, len(r.content)),
+		)
+	}
+
+	var body io.Reader = strings.NewReader(r.content[start : end+1])
+	if r.failAfter > 0 {
+		body = &brokenReader{r: body, limit: r.failAfter}
+		r.failAfter = 0
+	}
+
+	res.StatusCode = status
+	res.Status = http.StatusText(status)
+	res.ContentLength = int64(end + 1 - start)
+	res.Body = ioutil.NopCloser(body)
+
+	return res, nil
+}
+
+func TestDownloadVideoToResume(t *testing.T) {
+	invoker := createTestInvoker()
+	client := &RangeMock{
+		content:   strings.Repeat("giant bomb video ", 1000),
+		failAfter: 4000,
+	}
+	invoker.client = client
+
+	video := &VideoInfo{GUID: "2300-16713", HDURL: "https://giantbomb.com/video_hd.mp4"}
+	path := filepath.Join(t.TempDir(), "video.mp4")
+
+	err := invoker.DownloadVideoTo(context.Background(), video, QualityHD, path, nil)
+	if err == nil {
+		t.Fatal("interrupted download did not fail")
+	}
+
+	info, err := os.Stat(path + PartialSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 4000 {
+		t.Errorf("partial file was %d bytes expected %d", info.Size(), 4000)
+	}
+
+	var lastWritten, lastTotal int64
+	err = invoker.DownloadVideoTo(context.Background(), video, QualityHD, path, &DownloadOptions{
+		Progress: func(written, total int64) {
+			lastWritten, lastTotal = written, total
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.ranges[1] != "bytes=4000-" {
+		t.Errorf("resumed with range %q expected %q", client.ranges[1], "bytes=4000-")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != client.content {
+		t.Errorf("downloaded content does not match")
+	}
+
+	if lastWritten != int64(len(client.content)) || lastTotal != lastWritten {
+		t.Errorf("invalid progress %d/%d", lastWritten, lastTotal)
+	}
+
+	if _, err := os.Stat(path + PartialSuffix); !os.IsNotExist(err) {
+		t.Errorf("partial file was not removed")
+	}
+
+	err = invoker.DownloadVideoTo(context.Background(), video, QualityLow, path, nil)
+	if err == nil {
+		t.Errorf("downloaded missing quality")
+	}
+}
+
+func TestDownloadVideoToCompletePartial(t *testing.T) {
+	invoker := createTestInvoker()
+	client := &RangeMock{content: strings.Repeat("giant bomb video ", 100)}
+	invoker.client = client
+
+	video := &VideoInfo{GUID: "2300-16713", HDURL: "https://giantbomb.com/video_hd.mp4"}
+	path := filepath.Join(t.TempDir(), "video.mp4")
+
+	//the whole file was written but the process stopped before the rename
+	err := ioutil.WriteFile(path+PartialSuffix, []byte(client.content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = invoker.DownloadVideoTo(context.Background(), video, QualityHD, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != client.content {
+		t.Errorf("downloaded content does not match")
+	}
+
+	//a partial file longer than the video can't be completed
+	err = ioutil.WriteFile(path+PartialSuffix, []byte(client.content+"extra"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = invoker.DownloadVideoTo(context.Background(), video, QualityHD, path, nil)
+	if err == nil {
+		t.Errorf("completed a partial file longer than the video")
+	}
+}
@@ -0,0 +1,225 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//DefaultEpisodeTemplate the default file name template for episodes
+const DefaultEpisodeTemplate = "{publish_date} - {title}.mp3"
+
+const maxRedirects = 10
+
+//EpisodeOptions options for DownloadEpisode
+type EpisodeOptions struct {
+	//Channel the feed the entry is from, used for the album tag, {channel}
+	//and artwork when the entry has none
+	Channel *RSSChannel
+	//Template names the file relative to the directory, {channel},
+	//{publish_date}, {title} and {guid} are replaced, DefaultEpisodeTemplate
+	//if empty
+	Template string
+	//SkipArtwork leaves the picture out of the ID3 tag
+	SkipArtwork bool
+	Progress    func(written, total int64)
+}
+
+//EpisodeFileName returns the path of an episode relative to its directory
+func EpisodeFileName(entry *RSSFeedEntry, opts *EpisodeOptions) string {
+	template := DefaultEpisodeTemplate
+	channel := "Unknown Podcast"
+	if opts != nil && opts.Template != "" {
+		template = opts.Template
+	}
+	if opts != nil && opts.Channel != nil && opts.Channel.Title != "" {
+		channel = opts.Channel.Title
+	}
+
+	published := "unknown"
+	if !entry.PublishTime.IsZero() {
+		published = entry.PublishTime.Format(DateLayout)
+	}
+
+	name := strings.NewReplacer(
+		"{channel}", escapeFileName(channel),
+		"{publish_date}", published,
+		"{title}", escapeFileName(entry.Title),
+		"{guid}", escapeFileName(entry.GUID),
+	).Replace(template)
+
+	return filepath.FromSlash(name)
+}
+
+//DownloadEpisode saves an episode to dir following redirects such as
+//podtrac's, resuming a partial download, then writes its ID3 tag returning
+//the file's path, episodes already downloaded are not fetched again
+func (i *Invoker) DownloadEpisode(ctx context.Context, entry *RSSFeedEntry, dir string, opts *EpisodeOptions) (string, error) {
+	if opts == nil {
+		opts = &EpisodeOptions{}
+	}
+
+	path := filepath.Join(dir, EpisodeFileName(entry, opts))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	link, err := i.DownloadLink(entry)
+	if err != nil {
+		return "", err
+	}
+	link, err = i.resolveRedirects(ctx, link)
+	if err != nil {
+		return "", err
+	}
+
+	//tag the partial file before it is renamed so a finished file always
+	//has its tag
+	partial := path + PartialSuffix
+	err = i.downloadPartial(ctx, link, partial, &DownloadOptions{Progress: opts.Progress, apiHostOnly: true})
+	if err != nil {
+		return "", err
+	}
+
+	err = WriteID3(partial, i.episodeTag(ctx, entry, opts))
+	if err != nil {
+		return "", err
+	}
+
+	return path, os.Rename(partial, path)
+}
+
+//episodeTag builds the ID3 tag for an entry fetching its artwork, the tag is
+//left without a picture if the artwork can't be fetched
+func (i *Invoker) episodeTag(ctx context.Context, entry *RSSFeedEntry, opts *EpisodeOptions) *ID3Tag {
+	tag := &ID3Tag{
+		Title:   entry.Title,
+		Artist:  entry.Author,
+		Date:    entry.PublishTime,
+		Comment: entry.Summary,
+	}
+	if tag.Comment == "" {
+		tag.Comment = entry.Description
+	}
+
+	artwork := entry.Image.Href
+	if opts.Channel != nil {
+		tag.Album = opts.Channel.Title
+		if tag.Artist == "" {
+			tag.Artist = opts.Channel.Author
+		}
+		if artwork == "" {
+			artwork = opts.Channel.ITunesImage.Href
+		}
+	}
+
+	if opts.SkipArtwork || artwork == "" {
+		return tag
+	}
+
+	data, mime, err := i.fetchArtwork(ctx, artwork)
+	if err == nil {
+		tag.Artwork, tag.ArtworkMIME = data, mime
+	}
+
+	return tag
+}
+
+func (i *Invoker) fetchArtwork(ctx context.Context, link string) ([]byte, string, error) {
+	req, err := i.newPodcastRequest(ctx, "GET", link)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if i.isAPIHost(req.URL) {
+		err = i.requestLimiter(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	res, err := i.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("downloading artwork %s returned %s", link, res.Status)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	mime := res.Header.Get("Content-Type")
+	if mime == "" || mime == "application/octet-stream" {
+		mime = http.DetectContentType(data)
+	}
+
+	return data, mime, nil
+}
+
+//resolveRedirects follows redirects from link with HEAD requests returning
+//the final URL, the API key is only sent to and rate limited for the API host
+func (i *Invoker) resolveRedirects(ctx context.Context, link string) (string, error) {
+	for redirects := 0; redirects < maxRedirects; redirects++ {
+		req, err := i.newPodcastRequest(ctx, "HEAD", link)
+		if err != nil {
+			return "", err
+		}
+
+		if i.isAPIHost(req.URL) {
+			err = i.requestLimiter(ctx)
+			if err != nil {
+				return "", err
+			}
+		}
+		res, err := i.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		res.Body.Close()
+
+		location := res.Header.Get("Location")
+		if res.StatusCode < 300 || res.StatusCode >= 400 || location == "" {
+			//clients which follow redirects themselves report the last
+			//request they made
+			if res.Request != nil && res.Request.URL != nil {
+				return withoutAPIKey(res.Request.URL), nil
+			}
+			return link, nil
+		}
+
+		next, err := req.URL.Parse(location)
+		if err != nil {
+			return "", err
+		}
+		link = withoutAPIKey(next)
+	}
+
+	return "", fmt.Errorf("too many redirects downloading %s", link)
+}
+
+//withoutAPIKey returns u as a string removing any api_key the request added
+func withoutAPIKey(u *url.URL) string {
+	q := u.Query()
+	if _, ok := q["api_key"]; !ok {
+		return u.String()
+	}
+
+	stripped := *u
+	q.Del("api_key")
+	stripped.RawQuery = q.Encode()
+
+	return stripped.String()
+}
@@ -0,0 +1,190 @@
+package gbomb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+//EpisodeMock redirects podtrac links through giant bomb to a CDN and serves
+//audio and art
+type EpisodeMock struct {
+	audio  string
+	art    []byte
+	gets   int
+	leaked bool
+}
+
+func (e *EpisodeMock) Do(req *http.Request) (*http.Response, error) {
+	res := &http.Response{
+		Header:     make(http.Header),
+		StatusCode: http.StatusOK,
+		Status:     http.StatusText(http.StatusOK),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}
+
+	if req.URL.Host != "www.giantbomb.com" && req.URL.Query().Get("api_key") != "" {
+		e.leaked = true
+	}
+
+	switch req.URL.Host + req.URL.Path {
+	case "dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/3246/bombcast.mp3":
+		res.StatusCode = http.StatusFound
+		res.Status = http.StatusText(http.StatusFound)
+		res.Header.Set("Location", "https://www.giantbomb.com/podcasts/download/3246/bombcast.mp3")
+	case "www.giantbomb.com/podcasts/download/3246/bombcast.mp3":
+		if req.URL.Query().Get("api_key") != "coolbeans" {
+			return nil, fmt.Errorf("missing api key %s", req.URL)
+		}
+		res.StatusCode = http.StatusFound
+		res.Status = http.StatusText(http.StatusFound)
+		res.Header.Set("Location", "https://cdn.giantbomb.com/podcasts/3246/bombcast.mp3")
+	case "cdn.giantbomb.com/podcasts/3246/bombcast.mp3":
+		if req.Method == "GET" {
+			e.gets++
+			res.Body = ioutil.NopCloser(strings.NewReader(e.audio))
+			res.ContentLength = int64(len(e.audio))
+		}
+	case "giantbomb1.cbsistatic.com/uploads/original/0/30/3272163-041iaezvk6721.png":
+		if e.art == nil {
+			res.StatusCode = http.StatusNotFound
+			res.Status = http.StatusText(http.StatusNotFound)
+			break
+		}
+		res.Body = ioutil.NopCloser(bytes.NewReader(e.art))
+	default:
+		return nil, fmt.Errorf("unexpected URL %s", req.URL)
+	}
+
+	return res, nil
+}
+
+func TestDownloadEpisode(t *testing.T) {
+	oldTag := (&ID3Tag{Title: "Stale Title", Album: "Stale Album"}).Encode()
+	frames := "\xff\xfbaudio frames"
+	art := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+
+	client := &EpisodeMock{audio: string(oldTag) + frames, art: art}
+	invoker := createTestInvoker()
+	invoker.client = client
+	//only the HEAD to the API host may wait on the limiter
+	invoker.Limter = rate.NewLimiter(rate.Every(time.Hour), 1)
+
+	channel := &RSSChannel{Title: "Giant Bombcast", Author: "Giant Bomb"}
+	entry := &RSSFeedEntry{
+		Title:       "Giant Bombcast 672: Great Conversations",
+		GUID:        "1600-3246",
+		Summary:     "This week: the (digital) future of E3",
+		PublishTime: time.Date(2021, time.February, 9, 14, 52, 0, 0, pubDateZones["PST"]),
+		Image: ITunesImage{
+			Href: "https://giantbomb1.cbsistatic.com/uploads/original/0/30/3272163-041iaezvk6721.png",
+		},
+		Enclosure: RSSEnclosure{
+			URL: "https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/3246/bombcast.mp3",
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dir := t.TempDir()
+	opts := &EpisodeOptions{Channel: channel, Template: "{channel}/{publish_date} - {title}.mp3"}
+	path, err := invoker.DownloadEpisode(ctx, entry, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPath := filepath.Join(dir, "Giant Bombcast", "2021-02-09 - Giant Bombcast 672_ Great Conversations.mp3")
+	if path != expectedPath {
+		t.Errorf("saved to %s expected %s", path, expectedPath)
+	}
+	if client.leaked {
+		t.Errorf("api key sent to a host other than the endpoint")
+	}
+	if _, err := os.Stat(path + PartialSuffix + PartialSuffix); !os.IsNotExist(err) {
+		t.Errorf("downloaded to a doubled partial file")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), frames) || strings.Contains(string(data), "Stale") {
+		t.Errorf("old tag not replaced or audio lost")
+	}
+
+	tags, err := ReadID3Frames(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := map[string]string{
+		"TIT2": entry.Title,
+		"TALB": "Giant Bombcast",
+		"TPE1": "Giant Bomb",
+		"TDRC": "2021-02-09",
+	}
+	for id, expected := range text {
+		if frame := tags[id]; len(frame) == 0 || string(frame[1:]) != expected {
+			t.Errorf("invalid %s frame %q expected %q", id, frame, expected)
+		}
+	}
+	if comment := string(tags["COMM"]); !strings.HasSuffix(comment, "eng\x00"+entry.Summary) {
+		t.Errorf("invalid COMM frame %q", comment)
+	}
+	if picture := tags["APIC"]; !bytes.HasPrefix(picture, []byte("\x03image/png\x00\x03\x00")) || !bytes.HasSuffix(picture, art) {
+		t.Errorf("invalid APIC frame")
+	}
+
+	_, err = invoker.DownloadEpisode(ctx, entry, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.gets != 1 {
+		t.Errorf("downloaded %d times expected %d", client.gets, 1)
+	}
+}
+
+func TestDownloadEpisodeMissingArtwork(t *testing.T) {
+	client := &EpisodeMock{audio: "\xff\xfbaudio frames"}
+	invoker := createTestInvoker()
+	invoker.client = client
+
+	entry := &RSSFeedEntry{
+		Title: "Giant Bombcast 672: Great Conversations",
+		Image: ITunesImage{
+			Href: "https://giantbomb1.cbsistatic.com/uploads/original/0/30/3272163-041iaezvk6721.png",
+		},
+		Enclosure: RSSEnclosure{
+			URL: "https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/3246/bombcast.mp3",
+		},
+	}
+
+	path, err := invoker.DownloadEpisode(context.Background(), entry, t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := ReadID3Frames(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tags["APIC"]; ok {
+		t.Errorf("picture written without artwork")
+	}
+	if frame := tags["TIT2"]; len(frame) == 0 || string(frame[1:]) != entry.Title {
+		t.Errorf("invalid TIT2 frame %q", frame)
+	}
+}
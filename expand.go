@@ -0,0 +1,209 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const defaultExpandWorkers = 4
+
+//Expandable anything with an API detail URL that can be fetched in full
+type Expandable interface {
+	GetAPIDetailURL() string
+}
+
+//GetAPIDetailURL returns the API detail URL
+func (t Tag) GetAPIDetailURL() string {
+	return t.APIDetailURL
+}
+
+//GetAPIDetailURL returns the API detail URL
+func (a Association) GetAPIDetailURL() string {
+	return a.APIDetailURL
+}
+
+//GetAPIDetailURL returns the API detail URL
+func (v VideoCategory) GetAPIDetailURL() string {
+	return v.APIDetailURL
+}
+
+//GUIDFromDetailURL pulls the GUID out of an API detail URL
+//e.g. https://www.giantbomb.com/api/game/3030-56733/
+func GUIDFromDetailURL(detailURL string) (GUID, error) {
+	u, err := url.Parse(detailURL)
+	if err != nil {
+		return "", err
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	return ParseGUID(parts[len(parts)-1])
+}
+
+//Resource a giant bomb API resource without a dedicated type
+type Resource struct {
+	APIDetailURL  string          `json:"api_detail_url"`
+	SiteDetailURL string          `json:"site_detail_url"`
+	GUID          GUID            `json:"guid"`
+	ID            int             `json:"id"`
+	Name          string          `json:"name"`
+	Deck          string          `json:"deck"`
+	Image         Image           `json:"image"`
+	Raw           json.RawMessage `json:"-"`
+}
+
+type resourceResponseInternal struct {
+	ResponsePage
+	Results        json.RawMessage `json:"results"`
+	resourceName   string
+	targetResource GUID
+}
+
+//Path returns resource path
+func (r *resourceResponseInternal) Path() (string, map[string]string) {
+	return fmt.Sprintf("api/%s/%s", r.resourceName, r.targetResource), make(map[string]string)
+}
+
+//Parse parse
+func (r *resourceResponseInternal) Parse(data []byte) error {
+	var tmp resourceResponseInternal
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	r.ResponsePage = tmp.ResponsePage
+	r.Results = tmp.Results
+
+	return nil
+}
+
+//GetResource returns any resource by GUID with only the common fields decoded
+func (i *Invoker) GetResource(ctx context.Context, resourceID GUID) (*Resource, error) {
+	resource, err := i.typeTable().Lookup(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &resourceResponseInternal{}
+
+	result.resourceName = resource.DetailResourceName
+	result.targetResource = resourceID
+
+	body, err := i.GetContext(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out Resource
+	err = json.Unmarshal(result.Results, &out)
+	if err != nil {
+		return nil, err
+	}
+	out.Raw = result.Results
+
+	return &out, nil
+}
+
+//Expand fetches the full resource a tag points to see Resolve for the
+//returned types
+func (i *Invoker) Expand(ctx context.Context, tag Expandable) (interface{}, error) {
+	guid, err := GUIDFromDetailURL(tag.GetAPIDetailURL())
+	if err != nil {
+		return nil, err
+	}
+
+	return i.Resolve(ctx, guid)
+}
+
+//ExpandAll expands tags concurrently using at most workers goroutines
+//results are in the same order as tags
+func (i *Invoker) ExpandAll(ctx context.Context, tags []Expandable, workers int) ([]interface{}, error) {
+	if workers <= 0 {
+		workers = defaultExpandWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]interface{}, len(tags))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				result, err := i.Expand(ctx, tags[idx])
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[idx] = result
+			}
+		}()
+	}
+
+feed:
+	for idx := range tags {
+		select {
+		case jobs <- idx:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+//ExpandGames expands game tags such as Game.SimilarGames into full games
+func (i *Invoker) ExpandGames(ctx context.Context, tags []CompleteTag) ([]Game, error) {
+	expandables := make([]Expandable, len(tags))
+	for idx := range tags {
+		expandables[idx] = tags[idx]
+	}
+
+	results, err := i.ExpandAll(ctx, expandables, defaultExpandWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	games := make([]Game, len(results))
+	for idx, result := range results {
+		game, ok := result.(*Game)
+		if !ok {
+			return nil, fmt.Errorf(
+				"%s is a %T not a game", tags[idx].APIDetailURL, result,
+			)
+		}
+		games[idx] = *game
+	}
+
+	return games, nil
+}
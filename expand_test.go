@@ -0,0 +1,166 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+//RouteMock serves a test_data file per request URL, routes to an empty path
+//are answered with a 404
+type RouteMock struct {
+	mu     sync.Mutex
+	routes map[string]string
+	hits   map[string]int
+}
+
+func (r *RouteMock) Do(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path, ok := r.routes[req.URL.String()]
+	if !ok {
+		return nil, fmt.Errorf("unexpected URL %s", req.URL)
+	}
+
+	if r.hits == nil {
+		r.hits = make(map[string]int)
+	}
+	r.hits[req.URL.String()]++
+
+	if path == "" {
+		return &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("<html><body>Not Found</body></html>")),
+			StatusCode: 404,
+			Status:     "404",
+		}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Body:       file,
+		StatusCode: 200,
+		Status:     "200",
+	}, nil
+}
+
+func TestGUIDFromDetailURL(t *testing.T) {
+	guid, err := GUIDFromDetailURL("https://www.giantbomb.com/api/game/3030-56733/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if guid != "3030-56733" {
+		t.Errorf("invalid GUID %s expected %s", guid, "3030-56733")
+	}
+
+	if _, err := GUIDFromDetailURL("https://www.giantbomb.com/api/game/"); err == nil {
+		t.Errorf("parsed GUID from URL without one")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &RouteMock{
+		routes: map[string]string{
+			"https://www.giantbomb.com/api/franchise/3025-1?api_key=coolbeans&format=json&offset=0": "test_data/franchise.json",
+		},
+	}
+
+	result, err := invoker.Expand(context.Background(), CompleteTag{
+		Tag: Tag{
+			APIDetailURL: "https://www.giantbomb.com/api/franchise/3025-1/",
+			Name:         "Mario",
+		},
+		ID: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	franchise, ok := result.(*Resource)
+	if !ok {
+		t.Fatalf("expanded to %T expected *Resource", result)
+	}
+
+	if franchise.Name != "Mario" || franchise.GUID != "3025-1" {
+		t.Errorf("invalid franchise %s %s", franchise.Name, franchise.GUID)
+	}
+
+	if len(franchise.Raw) == 0 {
+		t.Errorf("raw resource was not kept")
+	}
+}
+
+func TestExpandGames(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &RouteMock{
+		routes: map[string]string{
+			"https://www.giantbomb.com/api/game/3030-14623?api_key=coolbeans&format=json&offset=0": "test_data/gameRequest.json",
+			"https://www.giantbomb.com/api/game/3030-2931?api_key=coolbeans&format=json&offset=0":  "test_data/gameRequest.json",
+		},
+	}
+	invoker.client = client
+
+	games, err := invoker.ExpandGames(context.Background(), game.SimilarGames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(games) != len(game.SimilarGames) {
+		t.Errorf(
+			"invalid number of games expanded %d expected %d",
+			len(games), len(game.SimilarGames),
+		)
+	}
+
+	for url, hits := range client.hits {
+		if hits != 1 {
+			t.Errorf("%s requested %d times", url, hits)
+		}
+	}
+
+	_, err = invoker.ExpandGames(context.Background(), game.Franchises)
+	if err == nil {
+		t.Errorf("expanded franchises as games")
+	}
+}
+
+func TestExpandCancelsRateLimit(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &RouteMock{}
+	invoker.Limter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	invoker.Limter.Allow()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := invoker.ExpandAll(ctx, []Expandable{
+		CompleteTag{Tag: Tag{APIDetailURL: "https://www.giantbomb.com/api/game/3030-2931/"}},
+	}, 1)
+	if err != context.Canceled {
+		t.Errorf("invalid error %v expected %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancel took %s", elapsed)
+	}
+}
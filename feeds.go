@@ -0,0 +1,129 @@
+package gbomb
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+//PodcastFeed a podcast feed GetPodcasts can fetch
+type PodcastFeed struct {
+	//ID the identifier passed to GetPodcasts e.g. bombcast
+	ID    string
+	Title string
+	//Path the feed's path relative to Invoker.Endpoint or an absolute URL for
+	//feeds hosted elsewhere
+	Path string
+	//Premium feeds are only served to premium members' API keys
+	Premium bool
+}
+
+//URL returns the feed's URL on endpoint
+func (f PodcastFeed) URL(endpoint string) string {
+	if strings.HasPrefix(f.Path, "http://") || strings.HasPrefix(f.Path, "https://") {
+		return f.Path
+	}
+
+	return fmt.Sprintf("%s/%s", strings.TrimRight(endpoint, "/"), strings.TrimLeft(f.Path, "/"))
+}
+
+//Giant bomb podcast feeds
+var (
+	FeedBombcast = PodcastFeed{
+		ID: "bombcast", Title: "Giant Bombcast", Path: "feeds/podcast/",
+	}
+	FeedBeastcast = PodcastFeed{
+		ID: "beastcast", Title: "Giant Beastcast", Path: "podcast-xml/beastcast/",
+	}
+	FeedBombinTheAM = PodcastFeed{
+		ID: "bombin-the-am", Title: "Bombin' the A.M.", Path: "podcast-xml/bombin-the-am/",
+		Premium: true,
+	}
+	FeedGiantBombPresents = PodcastFeed{
+		ID: "giant-bomb-presents", Title: "Giant Bomb Presents", Path: "podcast-xml/giant-bomb-presents/",
+	}
+	FeedPremium = PodcastFeed{
+		ID: "premium", Title: "Giant Bomb Premium", Path: "podcast-xml/premium/",
+		Premium: true,
+	}
+)
+
+//FeedCatalogue maps feed ids to feeds
+type FeedCatalogue map[string]PodcastFeed
+
+//Copy returns a copy of the catalogue
+func (c FeedCatalogue) Copy() FeedCatalogue {
+	result := make(FeedCatalogue, len(c))
+	for k, v := range c {
+		result[k] = v
+	}
+
+	return result
+}
+
+//Lookup returns the feed for an id, ids not in the catalogue are treated as
+//giant bomb podcast-xml slugs
+func (c FeedCatalogue) Lookup(id string) (PodcastFeed, bool) {
+	feed, ok := c[id]
+	if !ok {
+		return PodcastFeed{ID: id, Path: fmt.Sprintf("podcast-xml/%s/", id)}, false
+	}
+
+	return feed, true
+}
+
+//DefaultFeedCatalogue the giant bomb feeds known to the package
+var DefaultFeedCatalogue = FeedCatalogue{
+	FeedBombcast.ID:          FeedBombcast,
+	FeedBeastcast.ID:         FeedBeastcast,
+	FeedBombinTheAM.ID:       FeedBombinTheAM,
+	FeedGiantBombPresents.ID: FeedGiantBombPresents,
+	FeedPremium.ID:           FeedPremium,
+}
+
+func (i *Invoker) feedCatalogue() FeedCatalogue {
+	if i.Feeds == nil {
+		return DefaultFeedCatalogue
+	}
+
+	return i.Feeds
+}
+
+//ListPodcastFeeds returns the invoker's feeds ordered by id
+func (i *Invoker) ListPodcastFeeds() []PodcastFeed {
+	catalogue := i.feedCatalogue()
+
+	feeds := make([]PodcastFeed, 0, len(catalogue))
+	for _, feed := range catalogue {
+		feeds = append(feeds, feed)
+	}
+	sort.Slice(feeds, func(a, b int) bool {
+		return feeds[a].ID < feeds[b].ID
+	})
+
+	return feeds
+}
+
+//RegisterPodcastFeed adds a feed to the invoker's catalogue replacing any
+//with the same id
+func (i *Invoker) RegisterPodcastFeed(feed PodcastFeed) error {
+	if feed.ID == "" || feed.Path == "" {
+		return fmt.Errorf("podcast feed needs an id and path")
+	}
+	if _, err := url.Parse(feed.URL(i.Endpoint)); err != nil {
+		return err
+	}
+
+	if i.Feeds == nil {
+		i.Feeds = DefaultFeedCatalogue.Copy()
+	}
+	i.Feeds[feed.ID] = feed
+
+	return nil
+}
+
+//PodcastFeed returns the catalogue entry for a feed id
+func (i *Invoker) PodcastFeed(id string) (PodcastFeed, bool) {
+	return i.feedCatalogue().Lookup(id)
+}
@@ -0,0 +1,115 @@
+package gbomb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListPodcastFeeds(t *testing.T) {
+	invoker := createTestInvoker()
+
+	feeds := invoker.ListPodcastFeeds()
+	if len(feeds) != len(DefaultFeedCatalogue) {
+		t.Fatalf("listed %d feeds expected %d", len(feeds), len(DefaultFeedCatalogue))
+	}
+	for idx := 1; idx < len(feeds); idx++ {
+		if feeds[idx-1].ID >= feeds[idx].ID {
+			t.Errorf("feeds not ordered by id %s before %s", feeds[idx-1].ID, feeds[idx].ID)
+		}
+	}
+
+	if FeedBombcast.URL(invoker.Endpoint) != "https://www.giantbomb.com/feeds/podcast/" {
+		t.Errorf("invalid bombcast url %s", FeedBombcast.URL(invoker.Endpoint))
+	}
+
+	custom := PodcastFeed{ID: "jar-time", Title: "Jar Time", Path: "https://feeds.example.com/jar-time.xml"}
+	err := invoker.RegisterPodcastFeed(custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed, ok := invoker.PodcastFeed("jar-time"); !ok || feed != custom {
+		t.Errorf("custom feed not registered %+v", feed)
+	}
+	if _, ok := DefaultFeedCatalogue["jar-time"]; ok {
+		t.Errorf("registering modified the default catalogue")
+	}
+
+	if err := invoker.RegisterPodcastFeed(PodcastFeed{ID: "empty"}); err == nil {
+		t.Errorf("registered a feed without a path")
+	}
+
+	feed, ok := invoker.PodcastFeed("unknown-show")
+	if ok || feed.URL(invoker.Endpoint) != "https://www.giantbomb.com/podcast-xml/unknown-show/" {
+		t.Errorf("invalid fallback feed %+v", feed)
+	}
+}
+
+func TestGetPodcastFeedShapes(t *testing.T) {
+	invoker := createTestInvoker()
+	err := invoker.RegisterPodcastFeed(PodcastFeed{
+		ID: "mirror", Title: "Mirror", Path: "https://feeds.example.com/premium.xml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &RouteMock{
+		routes: map[string]string{
+			"https://www.giantbomb.com/podcast-xml/beastcast/?api_key=coolbeans":    "test_data/beastcast_feed.xml",
+			"https://www.giantbomb.com/podcast-xml/premium/?api_key=coolbeans":      "test_data/premium_feed.xml",
+			"https://feeds.example.com/premium.xml":                                 "test_data/premium_feed.xml",
+			"https://www.giantbomb.com/podcast-xml/unknown-show/?api_key=coolbeans": "",
+		},
+	}
+	invoker.client = client
+
+	beastcast, err := invoker.GetPodcasts("beastcast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if beastcast.Title != "Giant Beastcast" || len(beastcast.Entries) != 3 || !beastcast.Explicit {
+		t.Errorf("invalid beastcast channel %s %d entries", beastcast.Title, len(beastcast.Entries))
+	}
+	file, err := beastcast.Entries[0].File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.URL != "https://www.giantbomb.com/podcasts/download/3250/beastcast_300.mp3" || file.Length != 129278123 {
+		t.Errorf("invalid media content file %+v", file)
+	}
+	duration, err := beastcast.Entries[2].GetDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if duration != 112*time.Minute+45*time.Second {
+		t.Errorf("invalid duration %s", duration)
+	}
+
+	premium, err := invoker.GetPodcastFeed(FeedPremium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if premium.Image.URL == "" || len(premium.Entries) != 2 {
+		t.Errorf("invalid premium channel %+v", premium.Image)
+	}
+	file, err = premium.Entries[1].File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.URL != "https://www.giantbomb.com/podcasts/download/3244/bta_020921.mp3" {
+		t.Errorf("invalid enclosure %+v", file)
+	}
+
+	mirror, err := invoker.GetPodcasts("mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mirror.Title != "Giant Bomb Premium" {
+		t.Errorf("invalid custom feed title %s", mirror.Title)
+	}
+
+	_, err = invoker.GetPodcasts("unknown-show")
+	if err == nil {
+		t.Errorf("missing feed decoded")
+	}
+}
@@ -3,7 +3,6 @@ package gbomb
 import (
 	"context"
 	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,7 +13,8 @@ import (
 	"golang.org/x/time/rate"
 )
 
-type httpClient interface {
+//HTTPClient makes the invoker's requests, *http.Client satisfies it
+type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
@@ -23,20 +23,40 @@ type Invoker struct {
 	Endpoint string
 	APIKey   string
 	Limter   *rate.Limiter
-	client   httpClient
+	Types    TypeTable
+	Feeds    FeedCatalogue
+	//StrictDecoding when set responses with fields the structs do not map
+	//are returned as a *SchemaError instead of being silently dropped
+	StrictDecoding bool
+	//SynthesizePodcastLinks when set podcast entries without an enclosure or
+	//media:content are downloaded from a link built from their GUID
+	SynthesizePodcastLinks bool
+	client                 HTTPClient
 }
 
-func (i *Invoker) requestLimiter() {
-	i.Limter.Wait(context.TODO())
+//SetClient sets the client requests are made with e.g. one with timeouts
+func (i *Invoker) SetClient(client HTTPClient) {
+	i.client = client
+}
+
+//requestLimiter waits for the rate limiter returning early if ctx is done
+func (i *Invoker) requestLimiter(ctx context.Context) error {
+	return i.Limter.Wait(ctx)
 }
 
 func (i *Invoker) Get(pageable Pageable) ([]byte, error) {
+	return i.GetContext(context.Background(), pageable)
+}
+
+//GetContext gets pageable's current page, ctx cancels the request and any
+//wait for the rate limiter
+func (i *Invoker) GetContext(ctx context.Context, pageable Pageable) ([]byte, error) {
 	path, query := pageable.Path()
 
 	url := fmt.Sprintf("%s/%s", i.Endpoint, path)
 
-	req, err := http.NewRequest(
-		"GET", url, nil,
+	req, err := http.NewRequestWithContext(
+		ctx, "GET", url, nil,
 	)
 	if err != nil {
 		return nil, err
@@ -56,7 +76,10 @@ func (i *Invoker) Get(pageable Pageable) ([]byte, error) {
 	}
 	req.URL.RawQuery = q.Encode()
 
-	i.requestLimiter()
+	err = i.requestLimiter(ctx)
+	if err != nil {
+		return nil, err
+	}
 	res, err := i.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -68,6 +91,16 @@ func (i *Invoker) Get(pageable Pageable) ([]byte, error) {
 		return nil, err
 	}
 
+	if i.StrictDecoding {
+		issues, err := CheckSchema(body, pageable)
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) > 0 {
+			return nil, &SchemaError{Issues: issues}
+		}
+	}
+
 	return body, nil
 }
 
@@ -108,6 +141,8 @@ func CreateInvoker(endpoint, key string) *Invoker {
 	return &Invoker{
 		Endpoint: endpoint, APIKey: key,
 		Limter: rate.NewLimiter(rate.Every(time.Duration(31)*time.Second), 1),
+		Types:  DefaultTypeTable.Copy(),
+		Feeds:  DefaultFeedCatalogue.Copy(),
 		client: http.DefaultClient,
 	}
 }
@@ -122,42 +157,6 @@ type Pageable interface {
 	Parse(data []byte) error
 }
 
-//Date a giant bomb time
-type Date struct {
-	date string
-}
-
-//UnmarshalJSON custom json unmarshaler
-func (d *Date) UnmarshalJSON(data []byte) error {
-	//someone \" are being included
-	d.date = string(data[1 : len(data)-1])
-	return nil
-}
-
-//String returns date as string
-func (d *Date) String() string {
-	return d.date
-}
-
-//GetTime converts from giant bomb date to time.Time
-func (d *Date) GetTime() time.Time {
-	var layout string
-	if len(d.date) == 19 {
-		layout = "2006-01-02 15:04:05"
-	} else if len(d.date) == 10 {
-		layout = "2006-01-02"
-	} else {
-		layout = ""
-	}
-
-	result, _ := time.Parse(
-		layout,
-		string(d.date),
-	)
-
-	return result
-}
-
 //Image a giant bomb API Image
 type Image struct {
 	IconURL        string `json:"icon_url"`
@@ -172,6 +171,39 @@ type Image struct {
 	ImageTags      string `json:"image_tags"`
 }
 
+//UnmarshalJSON custom json unmarshaler Game.Images entries use original and
+//tags instead of original_url and image_tags
+func (img *Image) UnmarshalJSON(data []byte) error {
+	type imageAlias Image
+	var tmp struct {
+		imageAlias
+		Original string `json:"original"`
+		Tags     string `json:"tags"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	*img = Image(tmp.imageAlias)
+	if img.OriginalURL == "" {
+		img.OriginalURL = tmp.Original
+	}
+	if img.ImageTags == "" {
+		img.ImageTags = tmp.Tags
+	}
+
+	return nil
+}
+
+func (img *Image) knownFields() []string {
+	return []string{
+		"icon_url", "medium_url", "screen_url", "screen_large_url", "small_url",
+		"super_url", "thumb_url", "tiny_url", "original_url", "image_tags",
+		"original", "tags",
+	}
+}
+
 //Tag tag
 type Tag struct {
 	APIDetailURL string `json:"api_detail_url"`
@@ -208,7 +240,7 @@ type VideoShow struct {
 	APIDetailURL  string `json:"api_detail_url"`
 	ID            int    `json:"id"`
 	Title         string `json:"title"`
-	Postion       int    `json:"postion"`
+	Postion       int    `json:"position"`
 	SiteDetailURL string `json:"site_detail_url"`
 	Image         Image  `json:"image"`
 	Logo          Image  `json:"logo"`
@@ -218,8 +250,8 @@ type VideoShow struct {
 type Association struct {
 	APIDetailURL  string `json:"api_detail_url"`
 	SiteDetailURL string `json:"site_detail_url"`
-	GUID          string `json:"guid"`
-	ID            string `json:"id"`
+	GUID          GUID   `json:"guid"`
+	ID            int    `json:"id"`
 	Name          string `json:"name"`
 }
 
@@ -227,7 +259,7 @@ type Association struct {
 type VideoCategory struct {
 	APIDetailURL  string `json:"api_detail_url"`
 	SiteDetailURL string `json:"site_detail_url"`
-	ID            string `json:"id"`
+	ID            int    `json:"id"`
 	Name          string `json:"name"`
 }
 
@@ -235,8 +267,8 @@ type VideoCategory struct {
 type VideoInfo struct {
 	DetailURL       string          `json:"api_detail_url"`
 	SiteDetailURL   string          `json:"site_detail_url"`
-	GUID            string          `json:"guid"`
-	ID              string          `json:"id"`
+	GUID            GUID            `json:"guid"`
+	ID              int             `json:"id"`
 	Associations    []Association   `json:"associations"`
 	Deck            string          `json:"deck"`
 	EmbedPlayer     string          `json:"embed_player"`
@@ -244,6 +276,7 @@ type VideoInfo struct {
 	Name            string          `json:"name"`
 	Premium         bool            `json:"premium"`
 	PublishDate     Date            `json:"publish_date"`
+	Image           Image           `json:"image"`
 	User            string          `json:"user"`
 	Hosts           string          `json:"Hosts"`
 	Crew            string          `json:"crew"`
@@ -291,16 +324,16 @@ type Game struct {
 	Aliases                   string          `json:"aliases"`
 	APIDetailURL              string          `json:"api_detail_url"`
 	SiteDetailURL             string          `json:"site_detail_url"`
-	GUID                      string          `json:"guid"`
+	GUID                      GUID            `json:"guid"`
 	ID                        int             `json:"id"`
 	DateAdded                 Date            `json:"date_added"`
 	DateLastUpdate            Date            `json:"date_last_updated"`
-	Deck                      string          `json:"dec"`
+	Deck                      string          `json:"deck"`
 	Description               string          `json:"description"`
-	ExpectedReleaseDay        int             `json:"expected_release_day"`
-	ExpectedReleaseMonth      int             `json:"expected_release_month"`
-	ExpectedReleaseQuarter    int             `json:"expected_release_quarter"`
-	ExpectedReleaseYear       int             `json:"expected_release_year"`
+	ExpectedReleaseDay        *int            `json:"expected_release_day"`
+	ExpectedReleaseMonth      *int            `json:"expected_release_month"`
+	ExpectedReleaseQuarter    *int            `json:"expected_release_quarter"`
+	ExpectedReleaseYear       *int            `json:"expected_release_year"`
 	Image                     Image           `json:"image"`
 	ImageTags                 []ImageTag      `json:"image_tags"`
 	Images                    []Image         `json:"images"`
@@ -316,6 +349,7 @@ type Game struct {
 	FirstAppearanceCharacters []CompleteTag   `json:"first_appearance_characters"`
 	FirstAppearanceConcepts   []CompleteTag   `json:"first_appearance_concepts"`
 	FirstAppearanceLocations  []CompleteTag   `json:"first_appearance_locations"`
+	FirstAppearanceObjects    []CompleteTag   `json:"first_appearance_objects"`
 	FirstAppearancePeople     []CompleteTag   `json:"first_appearance_people"`
 	Franchises                []CompleteTag   `json:"franchises"`
 	Genres                    []CompleteTag   `json:"genres"`
@@ -329,6 +363,7 @@ type Game struct {
 	Reviews                   []CompleteTag   `json:"reviews"`
 	SimilarGames              []CompleteTag   `json:"similar_games"`
 	Themes                    []CompleteTag   `json:"themes"`
+	ResourceType              string          `json:"resource_type"`
 }
 
 //ResponsePage the page part of a response
@@ -339,6 +374,7 @@ type ResponsePage struct {
 	PageResults int    `json:"number_of_page_results"`
 	MaxResults  int    `json:"number_of_total_results"`
 	StatusCode  int    `json:"status_code"`
+	Version     string `json:"version"`
 }
 
 //NextOffset returns the next offset
@@ -382,15 +418,40 @@ func (r *ResponsePage) Complete() bool {
 	return r.Offset >= r.MaxResults
 }
 
+//VideoFilter limits a video listing to a show or category, zero fields are
+//not filtered on
+type VideoFilter struct {
+	Show     int
+	Category int
+}
+
+func (f VideoFilter) query() map[string]string {
+	var filters []string
+	if f.Show != 0 {
+		filters = append(filters, fmt.Sprintf("video_show:%d", f.Show))
+	}
+	if f.Category != 0 {
+		filters = append(filters, fmt.Sprintf("video_categories:%d", f.Category))
+	}
+
+	query := make(map[string]string)
+	if len(filters) > 0 {
+		query["filter"] = strings.Join(filters, ",")
+	}
+
+	return query
+}
+
 //VideosResponse videos response from giant bomb API
 type VideosResponse struct {
 	ResponsePage
 	Videos []VideoInfo `json:"results"`
+	filter VideoFilter
 }
 
 //Path returns video path
 func (v *VideosResponse) Path() (string, map[string]string) {
-	return fmt.Sprintf("api/videos"), make(map[string]string)
+	return fmt.Sprintf("api/videos"), v.filter.query()
 }
 
 //Parse parse
@@ -406,10 +467,15 @@ func (v *VideosResponse) Parse(data []byte) error {
 
 //GetVideos returns a base video
 func (i *Invoker) GetVideos(ctx context.Context, offset int) (*VideosResponse, error) {
-	result := &VideosResponse{}
+	return i.GetVideosFiltered(ctx, offset, VideoFilter{})
+}
+
+//GetVideosFiltered returns a page of videos matching filter
+func (i *Invoker) GetVideosFiltered(ctx context.Context, offset int, filter VideoFilter) (*VideosResponse, error) {
+	result := &VideosResponse{filter: filter}
 	result.Offset = offset
 
-	body, err := i.Get(result)
+	body, err := i.GetContext(ctx, result)
 	if err != nil {
 		return nil, err
 	}
@@ -421,7 +487,7 @@ func (i *Invoker) GetVideos(ctx context.Context, offset int) (*VideosResponse, e
 
 //DownloadVideo downloads a given video
 func (i *Invoker) DownloadVideo(ctx context.Context, url string) (io.ReadCloser, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -429,7 +495,10 @@ func (i *Invoker) DownloadVideo(ctx context.Context, url string) (io.ReadCloser,
 	q.Add("api_key", i.APIKey)
 	req.URL.RawQuery = q.Encode()
 
-	i.requestLimiter()
+	err = i.requestLimiter(ctx)
+	if err != nil {
+		return nil, err
+	}
 	res, err := i.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -438,10 +507,58 @@ func (i *Invoker) DownloadVideo(ctx context.Context, url string) (io.ReadCloser,
 	return res.Body, nil
 }
 
+type videoResponseInternal struct {
+	ResponsePage
+	Results     *VideoInfo `json:"results"`
+	targetVideo GUID
+}
+
+//Path returns video path
+func (v *videoResponseInternal) Path() (string, map[string]string) {
+	return fmt.Sprintf("api/video/%s", v.targetVideo), make(map[string]string)
+}
+
+//Parse parse
+func (v *videoResponseInternal) Parse(data []byte) error {
+	var tmp videoResponseInternal
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	v.ResponsePage = tmp.ResponsePage
+	v.Results = tmp.Results
+
+	return nil
+}
+
+//GetVideo returns a given video
+func (i *Invoker) GetVideo(ctx context.Context, videoID GUID) (*VideoInfo, error) {
+	if err := i.checkGUID(videoID, "video"); err != nil {
+		return nil, err
+	}
+
+	result := &videoResponseInternal{}
+
+	result.targetVideo = videoID
+
+	body, err := i.GetContext(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Results, nil
+}
+
 type gameResponseInternal struct {
 	ResponsePage
 	Results   *Game `json:"results"`
-	tagetGame string
+	tagetGame GUID
 }
 
 //Path returns video path
@@ -465,12 +582,16 @@ func (g *gameResponseInternal) Parse(data []byte) error {
 }
 
 //GetGame returns a given game
-func (i *Invoker) GetGame(ctx context.Context, gameID string) (*Game, error) {
+func (i *Invoker) GetGame(ctx context.Context, gameID GUID) (*Game, error) {
+	if err := i.checkGUID(gameID, "game"); err != nil {
+		return nil, err
+	}
+
 	result := &gameResponseInternal{}
 
 	result.tagetGame = gameID
 
-	body, err := i.Get(result)
+	body, err := i.GetContext(ctx, result)
 	if err != nil {
 		return nil, err
 	}
@@ -518,7 +639,7 @@ func (i *Invoker) SearchGame(ctx context.Context, name string) (*GamesResponse,
 
 	result.tagetGame = name
 
-	body, err := i.Get(result)
+	body, err := i.GetContext(ctx, result)
 	if err != nil {
 		return nil, err
 	}
@@ -530,97 +651,3 @@ func (i *Invoker) SearchGame(ctx context.Context, name string) (*GamesResponse,
 
 	return result, nil
 }
-
-// #####################################################################
-
-//RSSFeedEntry a giant bomb RSS feed entry
-type RSSFeedEntry struct {
-	Title      string `xml:"title"`
-	PubDateStr string `xml:"pubDate"`
-	GUID       string `xml:"guid"`
-	//This is constructed not pulled
-	link string
-}
-
-//GetPublishTime returns the publish time as a time object
-func (r *RSSFeedEntry) GetPublishTime() (time.Time, error) {
-	return time.Parse("Mon, 02 Jan 2006 15:04:05 MST", r.PubDateStr)
-}
-
-//Download returns a IO read Write closer of the download stream for an rss feed entry
-func (r *RSSFeedEntry) Download(i *Invoker) (io.ReadCloser, error) {
-	req, err := http.NewRequest("GET", r.link, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	q := req.URL.Query()
-	q.Add("api_key", i.APIKey)
-	req.URL.RawQuery = q.Encode()
-
-	i.requestLimiter()
-	res, err := i.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	return res.Body, nil
-}
-
-//RSSChannel a Giant bomb RSS channel
-type RSSChannel struct {
-	Title   string         `xml:"title"`
-	Entries []RSSFeedEntry `xml:"item"`
-}
-
-type rssBase struct {
-	Channel RSSChannel `xml:"channel"`
-}
-
-//GetPodcasts returns the RSSChannel Feed
-func (i *Invoker) GetPodcasts(feed string) (*RSSChannel, error) {
-	var middle string
-	if feed == "bombcast" {
-		middle = "feeds"
-		feed = "podcast"
-	} else {
-		middle = "podcast-xml"
-	}
-	url := fmt.Sprintf("%s/%s/%s/", i.Endpoint, middle, feed)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	q := req.URL.Query()
-	q.Add("api_key", i.APIKey)
-	req.URL.RawQuery = q.Encode()
-
-	i.requestLimiter()
-	res, err := i.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	bodyXML, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var rss rssBase
-	err = xml.Unmarshal([]byte(bodyXML), &rss)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := range rss.Channel.Entries {
-		guid := strings.Split(rss.Channel.Entries[i].GUID, "-")[1]
-		rss.Channel.Entries[i].link = fmt.Sprintf(
-			"https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/%s/audio.mp3",
-			guid,
-		)
-	}
-
-	return &rss.Channel, nil
-}
@@ -20,6 +20,29 @@ func createTestInvoker() *Invoker {
 	return invoker
 }
 
+//FileMock serves a test_data file when the request URL matches
+type FileMock struct {
+	expectedURL string
+	file        string
+}
+
+func (f *FileMock) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.String() != f.expectedURL {
+		return nil, fmt.Errorf("invalid URL %s expected %s", req.URL, f.expectedURL)
+	}
+
+	file, err := os.Open(f.file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Body:       file,
+		StatusCode: 200,
+		Status:     "200",
+	}, nil
+}
+
 type PodcastFeedMock struct {
 }
 
@@ -53,7 +76,7 @@ func TestRssChannel(t *testing.T) {
 
 	tme, _ := feed.Entries[0].GetPublishTime()
 	expectedTme, _ := time.Parse(
-		"2006-01-02 15:04:05 -0700 MST", "2021-02-09 14:52:00 +0000 PST",
+		"2006-01-02 15:04:05 -0700 MST", "2021-02-09 14:52:00 -0800 PST",
 	)
 	if !tme.Equal(expectedTme) {
 		t.Errorf(
@@ -62,11 +85,15 @@ func TestRssChannel(t *testing.T) {
 		)
 	}
 
-	expctedDownload := "https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/3246/audio.mp3"
-	if feed.Entries[0].link != expctedDownload {
+	expctedDownload := "https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/3246/vf_bc_672_020921_62-02-09-2021-2391958662.mp3"
+	link, err := invoker.DownloadLink(&feed.Entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link != expctedDownload {
 		t.Errorf(
-			"did not create download link correctly was %s expcted %s",
-			feed.Entries[0].link, expctedDownload,
+			"did not read download link correctly was %s expcted %s",
+			link, expctedDownload,
 		)
 	}
 }
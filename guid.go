@@ -0,0 +1,241 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//GUID a giant bomb GUID in the form <typeid>-<id> e.g. 3030-56733
+type GUID string
+
+//NewGUID creates a GUID from a type id and resource id
+func NewGUID(typeID, id int) GUID {
+	return GUID(fmt.Sprintf("%d-%d", typeID, id))
+}
+
+//ParseGUID parses and validates a giant bomb GUID
+func ParseGUID(s string) (GUID, error) {
+	g := GUID(s)
+	if err := g.Validate(); err != nil {
+		return "", err
+	}
+
+	return g, nil
+}
+
+func (g GUID) split() (int, int, error) {
+	parts := strings.Split(string(g), "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid GUID %q expected <typeid>-<id>", string(g))
+	}
+
+	typeID, err := strconv.Atoi(parts[0])
+	if err != nil || typeID <= 0 {
+		return 0, 0, fmt.Errorf("invalid GUID %q bad type id", string(g))
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || id <= 0 {
+		return 0, 0, fmt.Errorf("invalid GUID %q bad id", string(g))
+	}
+
+	return typeID, id, nil
+}
+
+//Validate returns an error if the GUID is not in the form <typeid>-<id>
+func (g GUID) Validate() error {
+	_, _, err := g.split()
+	return err
+}
+
+//TypeID returns the type id part of the GUID or 0 if invalid
+func (g GUID) TypeID() int {
+	typeID, _, _ := g.split()
+	return typeID
+}
+
+//ID returns the resource id part of the GUID or 0 if invalid
+func (g GUID) ID() int {
+	_, id, _ := g.split()
+	return id
+}
+
+//String returns the GUID as a string
+func (g GUID) String() string {
+	return string(g)
+}
+
+//ResourceType a giant bomb API resource type
+type ResourceType struct {
+	ID                 int    `json:"id"`
+	DetailResourceName string `json:"detail_resource_name"`
+	ListResourceName   string `json:"list_resource_name"`
+}
+
+//TypeTable maps type ids to resource types
+type TypeTable map[int]ResourceType
+
+//Lookup returns the resource type for a GUID
+func (t TypeTable) Lookup(g GUID) (ResourceType, error) {
+	typeID, _, err := g.split()
+	if err != nil {
+		return ResourceType{}, err
+	}
+
+	resource, ok := t[typeID]
+	if !ok {
+		return ResourceType{}, fmt.Errorf("unknown type id %d for GUID %s", typeID, g)
+	}
+
+	return resource, nil
+}
+
+//Copy returns a copy of the table
+func (t TypeTable) Copy() TypeTable {
+	result := make(TypeTable, len(t))
+	for k, v := range t {
+		result[k] = v
+	}
+
+	return result
+}
+
+//DefaultTypeTable built in giant bomb types used when /api/types has not been fetched
+var DefaultTypeTable = TypeTable{
+	1600: {1600, "podcast", "podcasts"},
+	1700: {1700, "promo", "promos"},
+	1900: {1900, "review", "reviews"},
+	2200: {2200, "user_review", "user_reviews"},
+	2300: {2300, "video", "videos"},
+	2320: {2320, "video_category", "video_categories"},
+	2340: {2340, "video_show", "video_shows"},
+	2450: {2450, "chat", "chats"},
+	3000: {3000, "accessory", "accessories"},
+	3005: {3005, "character", "characters"},
+	3010: {3010, "company", "companies"},
+	3015: {3015, "concept", "concepts"},
+	3020: {3020, "dlc", "dlcs"},
+	3025: {3025, "franchise", "franchises"},
+	3030: {3030, "game", "games"},
+	3032: {3032, "theme", "themes"},
+	3035: {3035, "location", "locations"},
+	3040: {3040, "person", "people"},
+	3045: {3045, "platform", "platforms"},
+	3050: {3050, "release", "releases"},
+	3055: {3055, "object", "objects"},
+	3060: {3060, "genre", "genres"},
+	3065: {3065, "game_rating", "game_ratings"},
+}
+
+//TypesResponse types response from giant bomb API
+type TypesResponse struct {
+	ResponsePage
+	Results []ResourceType `json:"results"`
+}
+
+//Path returns types path
+func (t *TypesResponse) Path() (string, map[string]string) {
+	return "api/types", make(map[string]string)
+}
+
+//Parse parse
+func (t *TypesResponse) Parse(data []byte) error {
+	var tmp TypesResponse
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	t.ResponsePage = tmp.ResponsePage
+	t.Results = tmp.Results
+
+	return nil
+}
+
+//GetTypes returns all resource types from the API
+func (i *Invoker) GetTypes(ctx context.Context) (*TypesResponse, error) {
+	result := &TypesResponse{}
+
+	body, err := i.GetContext(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+//RefreshTypes fetches /api/types and merges it into the invokers type table
+func (i *Invoker) RefreshTypes(ctx context.Context) error {
+	result, err := i.GetTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	if i.Types == nil {
+		i.Types = DefaultTypeTable.Copy()
+	}
+	for _, resource := range result.Results {
+		i.Types[resource.ID] = resource
+	}
+
+	return nil
+}
+
+func (i *Invoker) typeTable() TypeTable {
+	if i.Types == nil {
+		return DefaultTypeTable
+	}
+
+	return i.Types
+}
+
+func (i *Invoker) checkGUID(g GUID, resourceName string) error {
+	resource, err := i.typeTable().Lookup(g)
+	if err != nil {
+		return err
+	}
+
+	if resource.DetailResourceName != resourceName {
+		return fmt.Errorf(
+			"GUID %s is a %s expected %s", g, resource.DetailResourceName, resourceName,
+		)
+	}
+
+	return nil
+}
+
+//Resolve fetches the resource a GUID points to returning *Game, *VideoInfo,
+//*Promo, *Chat or *Resource for types without a dedicated getter
+func (i *Invoker) Resolve(ctx context.Context, g GUID) (interface{}, error) {
+	resource, err := i.typeTable().Lookup(g)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	switch resource.DetailResourceName {
+	case "game":
+		result, err = i.GetGame(ctx, g)
+	case "video":
+		result, err = i.GetVideo(ctx, g)
+	case "promo":
+		result, err = i.GetPromo(ctx, g)
+	case "chat":
+		result, err = i.GetChat(ctx, g)
+	default:
+		result, err = i.GetResource(ctx, g)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
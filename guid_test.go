@@ -0,0 +1,109 @@
+package gbomb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseGUID(t *testing.T) {
+	valid := map[string][2]int{
+		"3030-56733": {3030, 56733},
+		"1600-3246":  {1600, 3246},
+	}
+	for str, expected := range valid {
+		guid, err := ParseGUID(str)
+		if err != nil {
+			t.Errorf("failed to parse %s %v", str, err)
+			continue
+		}
+
+		if guid.TypeID() != expected[0] || guid.ID() != expected[1] {
+			t.Errorf(
+				"invalid parts for %s was %d %d expected %d %d",
+				str, guid.TypeID(), guid.ID(), expected[0], expected[1],
+			)
+		}
+	}
+
+	for _, str := range []string{"", "3030", "3030-", "-56733", "abc-123", "3030-56733-1"} {
+		if _, err := ParseGUID(str); err == nil {
+			t.Errorf("parsed invalid GUID %q", str)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	result, err := invoker.Resolve(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game, ok := result.(*Game)
+	if !ok {
+		t.Fatalf("resolved to %T expected *Game", result)
+	}
+
+	if game.GUID != "3030-56733" {
+		t.Errorf("invalid GUID %s expected %s", game.GUID, "3030-56733")
+	}
+
+	if _, err := invoker.GetGame(context.Background(), "1600-3246"); err == nil {
+		t.Errorf("GetGame accepted a podcast GUID")
+	}
+
+	invoker.client = &FileMock{
+		expectedURL: "https://www.giantbomb.com/api/video/2300-16713?api_key=coolbeans&format=json&offset=0",
+		file:        "test_data/video.json",
+	}
+	result, err = invoker.Resolve(context.Background(), "2300-16713")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	video, ok := result.(*VideoInfo)
+	if !ok {
+		t.Fatalf("resolved to %T expected *VideoInfo", result)
+	}
+
+	if video.ID != 16713 || video.Associations[0].ID != 70960 || video.VideoCategories[0].ID != 3 {
+		t.Errorf("invalid ids %d %d %d", video.ID, video.Associations[0].ID, video.VideoCategories[0].ID)
+	}
+	if video.Show.Postion != 2 || video.Image.SuperURL == "" {
+		t.Errorf("invalid show position %d or image %+v", video.Show.Postion, video.Image)
+	}
+}
+
+func TestRefreshTypes(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &FileMock{
+		expectedURL: "https://www.giantbomb.com/api/types?api_key=coolbeans&format=json&offset=0",
+		file:        "test_data/types.json",
+	}
+
+	if _, err := invoker.Types.Lookup("3070-1"); err == nil {
+		t.Errorf("found rating board before refresh")
+	}
+
+	err := invoker.RefreshTypes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource, err := invoker.Types.Lookup("3070-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resource.ListResourceName != "rating_boards" {
+		t.Errorf(
+			"invalid list resource name %s expected %s",
+			resource.ListResourceName, "rating_boards",
+		)
+	}
+
+	if _, ok := DefaultTypeTable[3070]; ok {
+		t.Errorf("refresh modified the default type table")
+	}
+}
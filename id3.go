@@ -0,0 +1,171 @@
+package gbomb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+//ID3Tag the fields written to an ID3v2.4 tag
+type ID3Tag struct {
+	Title   string
+	Album   string
+	Artist  string
+	Comment string
+	Date    time.Time
+	//Artwork front cover image data with its MIME type e.g. image/jpeg
+	Artwork     []byte
+	ArtworkMIME string
+}
+
+const id3HeaderSize = 10
+
+//id3TextEncodingUTF8 text frames are written as UTF-8 which ID3v2.4 allows
+const id3TextEncodingUTF8 = 3
+
+func synchsafe(n int) []byte {
+	return []byte{byte(n>>21) & 0x7f, byte(n>>14) & 0x7f, byte(n>>7) & 0x7f, byte(n) & 0x7f}
+}
+
+func unsynchsafe(b []byte) int {
+	return int(b[0])<<21 | int(b[1])<<14 | int(b[2])<<7 | int(b[3])
+}
+
+func writeID3Frame(buf *bytes.Buffer, id string, data []byte) {
+	buf.WriteString(id)
+	buf.Write(synchsafe(len(data)))
+	buf.Write([]byte{0, 0})
+	buf.Write(data)
+}
+
+func id3Text(s string) []byte {
+	return append([]byte{id3TextEncodingUTF8}, s...)
+}
+
+//Encode returns the tag as ID3v2.4 bytes, empty fields are left out
+func (t *ID3Tag) Encode() []byte {
+	var frames bytes.Buffer
+	if t.Title != "" {
+		writeID3Frame(&frames, "TIT2", id3Text(t.Title))
+	}
+	if t.Album != "" {
+		writeID3Frame(&frames, "TALB", id3Text(t.Album))
+	}
+	if t.Artist != "" {
+		writeID3Frame(&frames, "TPE1", id3Text(t.Artist))
+	}
+	if !t.Date.IsZero() {
+		writeID3Frame(&frames, "TDRC", id3Text(t.Date.Format(DateLayout)))
+	}
+	if t.Comment != "" {
+		//language then an empty short description
+		data := append([]byte{id3TextEncodingUTF8}, "eng"...)
+		data = append(data, 0)
+		writeID3Frame(&frames, "COMM", append(data, t.Comment...))
+	}
+	if len(t.Artwork) > 0 {
+		//mime, front cover picture type and an empty description
+		data := append([]byte{id3TextEncodingUTF8}, t.ArtworkMIME...)
+		data = append(data, 0, 3, 0)
+		writeID3Frame(&frames, "APIC", append(data, t.Artwork...))
+	}
+
+	var tag bytes.Buffer
+	tag.WriteString("ID3")
+	tag.Write([]byte{4, 0, 0})
+	tag.Write(synchsafe(frames.Len()))
+	tag.Write(frames.Bytes())
+
+	return tag.Bytes()
+}
+
+//id3Length returns the length of an ID3v2 tag at the start of header or 0
+func id3Length(header []byte) int {
+	if len(header) < id3HeaderSize || string(header[:3]) != "ID3" {
+		return 0
+	}
+
+	length := id3HeaderSize + unsynchsafe(header[6:10])
+	if header[5]&0x10 != 0 {
+		//footer present
+		length += id3HeaderSize
+	}
+
+	return length
+}
+
+//WriteID3 writes tag to the start of the MP3 at path replacing any existing
+//ID3v2 tag
+func WriteID3(path string, tag *ID3Tag) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	header := make([]byte, id3HeaderSize)
+	n, err := io.ReadFull(src, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	skip := id3Length(header[:n])
+	_, err = src.Seek(int64(skip), io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.Create(path + ".id3")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dst.Name())
+	defer dst.Close()
+
+	_, err = dst.Write(tag.Encode())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		return err
+	}
+
+	err = dst.Close()
+	if err != nil {
+		return err
+	}
+	src.Close()
+
+	return os.Rename(dst.Name(), path)
+}
+
+//ReadID3Frames returns the frames of an ID3v2.4 tag at the start of data
+//keyed by frame id
+func ReadID3Frames(data []byte) (map[string][]byte, error) {
+	length := id3Length(data)
+	if length == 0 {
+		return nil, fmt.Errorf("no ID3v2 tag")
+	}
+	if data[3] != 4 {
+		return nil, fmt.Errorf("unsupported ID3v2.%d tag", data[3])
+	}
+	if length > len(data) {
+		return nil, fmt.Errorf("truncated ID3v2 tag")
+	}
+
+	frames := make(map[string][]byte)
+	body := data[id3HeaderSize:length]
+	for len(body) >= id3HeaderSize && body[0] != 0 {
+		size := unsynchsafe(body[4:8])
+		if id3HeaderSize+size > len(body) {
+			return nil, fmt.Errorf("truncated %s frame", body[:4])
+		}
+
+		frames[string(body[:4])] = body[id3HeaderSize : id3HeaderSize+size]
+		body = body[id3HeaderSize+size:]
+	}
+
+	return frames, nil
+}
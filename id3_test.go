@@ -0,0 +1,43 @@
+package gbomb
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteID3(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+	err := ioutil.WriteFile(path, []byte("\xff\xfbuntagged"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WriteID3(path, &ID3Tag{Title: "Bombin' the A.M."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteID3(path, &ID3Tag{Title: "Giant Beastcast", Album: "Beastcast"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames, err := ReadID3Frames(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 || string(frames["TIT2"][1:]) != "Giant Beastcast" {
+		t.Errorf("invalid frames %q", frames)
+	}
+	if string(data[id3Length(data):]) != "\xff\xfbuntagged" {
+		t.Errorf("audio changed by tagging")
+	}
+
+	if _, err := ReadID3Frames([]byte("\xff\xfbuntagged")); err == nil {
+		t.Errorf("read frames from untagged data")
+	}
+}
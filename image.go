@@ -0,0 +1,181 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+//ImageVariant one of the sizes giant bomb serves an Image in
+type ImageVariant int
+
+//Image variants ordered from smallest to largest
+const (
+	ImageTiny ImageVariant = iota
+	ImageIcon
+	ImageThumb
+	ImageSmall
+	ImageScreen
+	ImageMedium
+	ImageScreenLarge
+	ImageSuper
+	ImageOriginal
+)
+
+//ImageVariants every variant from smallest to largest
+var ImageVariants = []ImageVariant{
+	ImageTiny, ImageIcon, ImageThumb, ImageSmall, ImageScreen,
+	ImageMedium, ImageScreenLarge, ImageSuper, ImageOriginal,
+}
+
+//String returns the variant as a string
+func (v ImageVariant) String() string {
+	switch v {
+	case ImageTiny:
+		return "tiny"
+	case ImageIcon:
+		return "icon"
+	case ImageThumb:
+		return "thumb"
+	case ImageSmall:
+		return "small"
+	case ImageScreen:
+		return "screen"
+	case ImageMedium:
+		return "medium"
+	case ImageScreenLarge:
+		return "screen_large"
+	case ImageSuper:
+		return "super"
+	case ImageOriginal:
+		return "original"
+	}
+
+	return fmt.Sprintf("ImageVariant(%d)", int(v))
+}
+
+//Width returns the approximate max width in pixels giant bomb scales the
+//variant to, 0 for ImageOriginal which is not scaled
+func (v ImageVariant) Width() int {
+	switch v {
+	case ImageTiny:
+		return 36
+	case ImageIcon:
+		return 80
+	case ImageThumb:
+		return 100
+	case ImageSmall:
+		return 320
+	case ImageScreen:
+		return 480
+	case ImageMedium:
+		return 640
+	case ImageScreenLarge:
+		return 960
+	case ImageSuper:
+		return 1280
+	}
+
+	return 0
+}
+
+//URL returns the URL for a variant
+func (img *Image) URL(variant ImageVariant) string {
+	switch variant {
+	case ImageTiny:
+		return img.TinyURL
+	case ImageIcon:
+		return img.IconURL
+	case ImageThumb:
+		return img.ThumbURL
+	case ImageSmall:
+		return img.SmallURL
+	case ImageScreen:
+		return img.ScreenURL
+	case ImageMedium:
+		return img.MediumURL
+	case ImageScreenLarge:
+		return img.ScreenLargeURL
+	case ImageSuper:
+		return img.SuperURL
+	case ImageOriginal:
+		return img.OriginalURL
+	}
+
+	return ""
+}
+
+//Best returns the largest variant no wider than maxWidth that has a URL
+//falling back to the smallest available variant, maxWidth <= 0 means no limit
+func (img *Image) Best(maxWidth int) (ImageVariant, bool) {
+	for i := len(ImageVariants) - 1; i >= 0; i-- {
+		variant := ImageVariants[i]
+		if img.URL(variant) == "" {
+			continue
+		}
+
+		if maxWidth <= 0 || (variant.Width() != 0 && variant.Width() <= maxWidth) {
+			return variant, true
+		}
+	}
+
+	for _, variant := range ImageVariants {
+		if img.URL(variant) != "" {
+			return variant, true
+		}
+	}
+
+	return 0, false
+}
+
+//isAPIHost returns if a URL is served by the API endpoint rather than a CDN
+func (i *Invoker) isAPIHost(target *url.URL) bool {
+	endpoint, err := url.Parse(i.Endpoint)
+	if err != nil {
+		return true
+	}
+
+	return target.Host == endpoint.Host
+}
+
+//DownloadImage writes the given variant of an image to w, requests to the API
+//host are rate limited and carry the API key while CDN hosts are fetched
+//directly
+func (i *Invoker) DownloadImage(ctx context.Context, img *Image, variant ImageVariant, w io.Writer) error {
+	imageURL := img.URL(variant)
+	if imageURL == "" {
+		return fmt.Errorf("image has no %s variant", variant)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return err
+	}
+
+	if i.isAPIHost(req.URL) {
+		q := req.URL.Query()
+		q.Add("api_key", i.APIKey)
+		req.URL.RawQuery = q.Encode()
+
+		err = i.requestLimiter(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	res, err := i.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s returned %s", imageURL, res.Status)
+	}
+
+	_, err = io.Copy(w, res.Body)
+
+	return err
+}
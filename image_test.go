@@ -0,0 +1,94 @@
+package gbomb
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestImageBest(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[int]ImageVariant{
+		0:    ImageOriginal,
+		2000: ImageSuper,
+		500:  ImageScreen,
+		10:   ImageTiny,
+	}
+	for maxWidth, expected := range cases {
+		variant, ok := game.Image.Best(maxWidth)
+		if !ok || variant != expected {
+			t.Errorf("best for %d was %s expected %s", maxWidth, variant, expected)
+		}
+	}
+
+	//game.Images entries have no screen_large_url
+	variant, _ := game.Images[0].Best(1000)
+	if variant != ImageMedium {
+		t.Errorf("best for %d was %s expected %s", 1000, variant, ImageMedium)
+	}
+
+	if _, ok := (&Image{}).Best(0); ok {
+		t.Errorf("found a variant on an empty image")
+	}
+}
+
+type ImageMock struct {
+	requested string
+}
+
+func (i *ImageMock) Do(req *http.Request) (*http.Response, error) {
+	i.requested = req.URL.String()
+
+	return &http.Response{
+		Body:       ioutil.NopCloser(strings.NewReader("jpeg")),
+		StatusCode: 200,
+		Status:     "200",
+	}, nil
+}
+
+func TestDownloadImage(t *testing.T) {
+	invoker := createTestInvoker()
+	client := &ImageMock{}
+	invoker.client = client
+
+	img := &Image{
+		TinyURL:     "https://giantbomb1.cbsistatic.com/uploads/square_mini/8/82063/2946176-smobox.jpg",
+		OriginalURL: "https://www.giantbomb.com/a/uploads/original/8/82063/2946176-smobox.jpg",
+	}
+
+	var buf bytes.Buffer
+	err := invoker.DownloadImage(context.Background(), img, ImageTiny, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "jpeg" {
+		t.Errorf("invalid body %s", buf.String())
+	}
+
+	if client.requested != img.TinyURL {
+		t.Errorf("CDN request was %s expected %s", client.requested, img.TinyURL)
+	}
+
+	err = invoker.DownloadImage(context.Background(), img, ImageOriginal, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.requested != img.OriginalURL+"?api_key=coolbeans" {
+		t.Errorf("API host request was %s", client.requested)
+	}
+
+	if err := invoker.DownloadImage(context.Background(), img, ImageSuper, &buf); err == nil {
+		t.Errorf("downloaded missing variant")
+	}
+}
@@ -0,0 +1,364 @@
+package gbomb
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+//DefaultSiteURL used to turn relative description links into absolute ones
+const DefaultSiteURL = "https://www.giantbomb.com"
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`",
+)
+
+//LinkResolver returns the URL a description cross link should point to
+type LinkResolver func(ref DescriptionRef) string
+
+//SiteLinkResolver links cross links to their page on siteURL
+func SiteLinkResolver(siteURL string) LinkResolver {
+	return func(ref DescriptionRef) string {
+		if strings.HasPrefix(ref.Path, "/") {
+			return strings.TrimRight(siteURL, "/") + ref.Path
+		}
+
+		return ref.Path
+	}
+}
+
+//DescriptionMarkdown converts giant bomb wiki HTML to Markdown, cross links
+//are passed through resolve
+func DescriptionMarkdown(html string, resolve LinkResolver) (string, error) {
+	if resolve == nil {
+		resolve = SiteLinkResolver(DefaultSiteURL)
+	}
+
+	c := &markdownConverter{resolve: resolve}
+
+	err := walkDescription(html, c)
+	if err != nil {
+		return "", err
+	}
+	c.block()
+
+	return strings.TrimSpace(c.out.String()) + "\n", nil
+}
+
+type markdownLink struct {
+	url  string
+	text strings.Builder
+}
+
+type markdownConverter struct {
+	resolve LinkResolver
+	out     strings.Builder
+	line    strings.Builder
+
+	links  []*markdownLink
+	lists  []string
+	rows   [][]string
+	cell   *strings.Builder
+	header bool
+}
+
+func (c *markdownConverter) write(s string) {
+	if len(c.links) > 0 {
+		c.links[len(c.links)-1].text.WriteString(s)
+		return
+	}
+	if c.cell != nil {
+		c.cell.WriteString(s)
+		return
+	}
+
+	c.line.WriteString(s)
+}
+
+//block ends the current paragraph
+func (c *markdownConverter) block() {
+	line := strings.TrimSpace(collapseSpace(c.line.String()))
+	c.line.Reset()
+	if line == "" {
+		return
+	}
+
+	c.out.WriteString(line)
+	c.out.WriteString("\n\n")
+}
+
+func (c *markdownConverter) start(name string, t xml.StartElement) {
+	if level := headingLevel(name); level > 0 {
+		c.block()
+		c.write(strings.Repeat("#", level) + " ")
+		return
+	}
+
+	switch name {
+	case "p", "blockquote":
+		if c.cell == nil && len(c.lists) == 0 {
+			c.block()
+		} else {
+			c.write(" ")
+		}
+	case "br":
+		c.write(" ")
+	case "strong", "b":
+		c.write("**")
+	case "em", "i":
+		c.write("*")
+	case "a":
+		href := attr(t, "href")
+		if ref, ok := descriptionRef(t); ok {
+			href = c.resolve(ref)
+		} else if strings.HasPrefix(href, "/") {
+			href = strings.TrimRight(DefaultSiteURL, "/") + href
+		}
+		c.links = append(c.links, &markdownLink{url: href})
+	case "ul", "ol":
+		if len(c.lists) == 0 {
+			c.block()
+		}
+		c.lists = append(c.lists, name)
+	case "li":
+		c.listItem()
+		marker := "-"
+		if c.lists[len(c.lists)-1] == "ol" {
+			marker = "1."
+		}
+		c.write(strings.Repeat("  ", len(c.lists)-1) + marker + " ")
+	case "table":
+		c.block()
+		c.rows = nil
+		c.header = false
+	case "tr":
+		c.rows = append(c.rows, nil)
+	case "td", "th":
+		if name == "th" && len(c.rows) == 1 {
+			c.header = true
+		}
+		c.cell = &strings.Builder{}
+	}
+}
+
+//listItem ends the previous list item
+func (c *markdownConverter) listItem() {
+	if len(c.lists) == 0 {
+		c.lists = append(c.lists, "ul")
+	}
+
+	line := strings.TrimRight(collapseListSpace(c.line.String()), " ")
+	c.line.Reset()
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	c.out.WriteString(line)
+	c.out.WriteString("\n")
+}
+
+func (c *markdownConverter) end(name string) {
+	if headingLevel(name) > 0 {
+		c.block()
+		return
+	}
+
+	switch name {
+	case "p", "blockquote":
+		if c.cell == nil && len(c.lists) == 0 {
+			c.block()
+		}
+	case "strong", "b":
+		c.write("**")
+	case "em", "i":
+		c.write("*")
+	case "a":
+		if len(c.links) == 0 {
+			return
+		}
+		link := c.links[len(c.links)-1]
+		c.links = c.links[:len(c.links)-1]
+		text := collapseSpace(link.text.String())
+		if link.url == "" {
+			c.write(text)
+			return
+		}
+		c.write(fmt.Sprintf("[%s](%s)", text, link.url))
+	case "li":
+		c.listItem()
+	case "ul", "ol":
+		if len(c.lists) > 0 {
+			c.lists = c.lists[:len(c.lists)-1]
+		}
+		if len(c.lists) == 0 {
+			c.listItem()
+			c.out.WriteString("\n")
+		}
+	case "td", "th":
+		if c.cell != nil && len(c.rows) > 0 {
+			cell := strings.TrimSpace(collapseSpace(c.cell.String()))
+			cell = strings.Replace(cell, "|", `\|`, -1)
+			c.rows[len(c.rows)-1] = append(c.rows[len(c.rows)-1], cell)
+		}
+		c.cell = nil
+	case "table":
+		c.table()
+	}
+}
+
+func (c *markdownConverter) text(s string) {
+	c.write(markdownEscaper.Replace(s))
+}
+
+func (c *markdownConverter) image(img DescriptionImage) {
+	image := fmt.Sprintf("![%s](%s)", markdownEscaper.Replace(img.Caption), img.URL)
+	if c.cell != nil {
+		c.cell.WriteString(" " + image)
+		return
+	}
+
+	c.block()
+	c.out.WriteString(image + "\n\n")
+}
+
+//table writes the collected rows as a markdown table
+func (c *markdownConverter) table() {
+	columns := 0
+	for _, row := range c.rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return
+	}
+
+	rows := c.rows
+	if !c.header {
+		rows = append([][]string{make([]string, columns)}, rows...)
+	}
+
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		c.out.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			c.out.WriteString(strings.Repeat("| --- ", columns) + "|\n")
+		}
+	}
+	c.out.WriteString("\n")
+	c.rows = nil
+}
+
+//collapseListSpace collapses whitespace keeping leading indentation
+func collapseListSpace(s string) string {
+	trimmed := strings.TrimLeft(s, " ")
+	return s[:len(s)-len(trimmed)] + collapseSpace(trimmed)
+}
+
+//MarkdownRenderer renders games and videos as Markdown pages
+type MarkdownRenderer struct {
+	GameTemplate  *template.Template
+	VideoTemplate *template.Template
+	//Links resolves description cross links, giant bomb site links if nil
+	Links LinkResolver
+}
+
+//DefaultGameMarkdown the default game page template
+const DefaultGameMarkdown = `# {{ .Name }}
+{{ with .Image.SuperURL }}
+![{{ $.Name }}]({{ . }})
+{{ end }}
+{{ with .Deck }}> {{ . }}
+{{ end }}
+| | |
+| --- | --- |
+| Release | {{ release . }} |
+{{- with .Platforms }}
+| Platforms | {{ platforms . }} |
+{{- end }}
+{{- with .Developers }}
+| Developers | {{ names . }} |
+{{- end }}
+{{- with .Publishers }}
+| Publishers | {{ names . }} |
+{{- end }}
+{{- with .Genres }}
+| Genres | {{ names . }} |
+{{- end }}
+| Giant Bomb | [{{ .GUID }}]({{ .SiteDetailURL }}) |
+
+{{ markdown .Description }}`
+
+//DefaultVideoMarkdown the default video page template
+const DefaultVideoMarkdown = `# {{ .Name }}
+{{ with .Show.Title }}
+*{{ . }}*
+{{ end }}
+{{ with .Deck }}> {{ . }}
+{{ end }}
+| | |
+| --- | --- |
+| Published | {{ .PublishDate }} |
+| Length | {{ .LengthDuration }} |
+{{- with .Hosts }}
+| Hosts | {{ . }} |
+{{- end }}
+| Watch | [{{ .GUID }}]({{ .SiteDetailURL }}) |
+{{ with .Associations }}
+## Related
+
+{{ range . }}- [{{ .Name }}]({{ .SiteDetailURL }})
+{{ end }}{{ end }}`
+
+//NewMarkdownRenderer Creates a renderer with the default templates
+func NewMarkdownRenderer() *MarkdownRenderer {
+	r := &MarkdownRenderer{}
+	r.GameTemplate = template.Must(r.NewTemplate("game").Parse(DefaultGameMarkdown))
+	r.VideoTemplate = template.Must(r.NewTemplate("video").Parse(DefaultVideoMarkdown))
+
+	return r
+}
+
+//NewTemplate returns a template with the renderer's functions available
+//markdown converts description HTML, names joins tag names, platforms joins
+//platform abbreviations and release formats a game's ReleaseWindow
+func (r *MarkdownRenderer) NewTemplate(name string) *template.Template {
+	return template.New(name).Funcs(template.FuncMap{
+		"markdown": func(html string) (string, error) {
+			return DescriptionMarkdown(html, r.Links)
+		},
+		"names": func(tags []CompleteTag) string {
+			names := make([]string, len(tags))
+			for i, tag := range tags {
+				names[i] = tag.Name
+			}
+			return strings.Join(names, ", ")
+		},
+		"platforms": func(tags []PlatformTag) string {
+			names := make([]string, len(tags))
+			for i, tag := range tags {
+				names[i] = tag.Abbreviation
+				if names[i] == "" {
+					names[i] = tag.Name
+				}
+			}
+			return strings.Join(names, ", ")
+		},
+		"release": func(g *Game) string {
+			return g.ReleaseWindow().String()
+		},
+	})
+}
+
+//RenderGame writes a game as Markdown
+func (r *MarkdownRenderer) RenderGame(w io.Writer, g *Game) error {
+	return r.GameTemplate.Execute(w, g)
+}
+
+//RenderVideo writes a video as Markdown
+func (r *MarkdownRenderer) RenderVideo(w io.Writer, v *VideoInfo) error {
+	return r.VideoTemplate.Execute(w, v)
+}
@@ -0,0 +1,138 @@
+package gbomb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDescriptionMarkdown(t *testing.T) {
+	md, err := DescriptionMarkdown(
+		`<h2>Overview</h2><p>Made by <a href="/nintendo-epd/3010-11421/" data-ref-id="3010-11421">Nintendo EPD</a> for <strong>Switch</strong> &amp; more_stuff.</p>`+
+			`<ul><li>One</li><li>Two</li></ul>`+
+			`<table><thead><tr><th>Capture</th><th>Picture</th></tr></thead><tbody><tr><td>Frog</td><td><figure data-img-src="https://static.giantbomb.com/frog.jpg"><img alt="No Caption Provided" src="x.jpg"></figure></td></tr></tbody></table>`,
+		func(ref DescriptionRef) string {
+			return "/wiki/" + ref.GUID.String()
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "## Overview\n\n" +
+		"Made by [Nintendo EPD](/wiki/3010-11421) for **Switch** & more\\_stuff.\n\n" +
+		"- One\n- Two\n\n" +
+		"| Capture | Picture |\n| --- | --- |\n| Frog | ![](https://static.giantbomb.com/frog.jpg) |\n"
+	if md != expected {
+		t.Errorf("invalid markdown\n%s\nexpected\n%s", md, expected)
+	}
+}
+
+func TestDescriptionMarkdownMatchesParse(t *testing.T) {
+	html := `<p>See <a href="/mario/3005-1/" data-ref-id="3005-1">Mario</a><noscript><img src="tracker.gif"></noscript></p>` +
+		`<figure data-img-src="https://static.giantbomb.com/a.jpg" data-ref-id="1300-1">` +
+		`<img src="https://static.giantbomb.com/b.jpg" alt="No Caption Provided"><figcaption>A <a href="/x/">caption</a></figcaption></figure>`
+
+	desc, err := ParseDescription(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err := DescriptionMarkdown(html, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images, refs := desc.Images(), desc.Refs()
+	if len(images) != 1 || len(refs) != 1 {
+		t.Fatalf("parsed %d images and %d refs expected 1 of each", len(images), len(refs))
+	}
+
+	expected := fmt.Sprintf(
+		"See [%s](%s%s)\n\n![%s](%s)\n", refs[0].Text, DefaultSiteURL, refs[0].Path, images[0].Caption, images[0].URL,
+	)
+	if md != expected {
+		t.Errorf("invalid markdown\n%s\nexpected\n%s", md, expected)
+	}
+}
+
+func TestRenderGame(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renderer := NewMarkdownRenderer()
+	var buf bytes.Buffer
+	err = renderer.RenderGame(&buf, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := buf.String()
+	for _, expected := range []string{
+		"# Super Mario Odyssey\n",
+		"| Release | October 27, 2017 |\n",
+		"| Platforms | NSW |\n",
+		"| Developers | Nintendo EPD, 1-UP Studio |\n",
+		"## Overview\n\nSuper Mario Odyssey is a 3D platformer developed by [Nintendo EPD](https://www.giantbomb.com/nintendo-epd/3010-11421/)",
+	} {
+		if !strings.Contains(md, expected) {
+			t.Errorf("rendered game is missing %q", expected)
+		}
+	}
+	if strings.Contains(md, "<p>") {
+		t.Errorf("rendered game contains HTML")
+	}
+
+	renderer.GameTemplate = renderer.NewTemplate("custom")
+	_, err = renderer.GameTemplate.Parse(`{{ .Name }} ({{ release . }}) by {{ names .Developers }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	err = renderer.RenderGame(&buf, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Super Mario Odyssey (October 27, 2017) by Nintendo EPD, 1-UP Studio"
+	if buf.String() != expected {
+		t.Errorf("invalid custom render %s expected %s", buf.String(), expected)
+	}
+}
+
+func TestRenderVideo(t *testing.T) {
+	video := &VideoInfo{
+		Name:          "Quick Look: Hitman 3",
+		GUID:          "2300-16713",
+		SiteDetailURL: "https://www.giantbomb.com/shows/quick-look-hitman-3/2300-16713/",
+		LengthSeconds: 3725,
+		Show:          VideoShow{Title: "Quick Looks"},
+		Associations: []Association{
+			{Name: "Hitman 3", SiteDetailURL: "https://www.giantbomb.com/hitman-3/3030-79488/"},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := NewMarkdownRenderer().RenderVideo(&buf, video)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := buf.String()
+	for _, expected := range []string{
+		"# Quick Look: Hitman 3\n",
+		"*Quick Looks*",
+		"| Length | 1h2m5s |",
+		"- [Hitman 3](https://www.giantbomb.com/hitman-3/3030-79488/)",
+	} {
+		if !strings.Contains(md, expected) {
+			t.Errorf("rendered video is missing %q\n%s", expected, md)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package gbomb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const mirrorIndexFile = "index.json"
+
+var imageType = reflect.TypeOf(Image{})
+
+//ImageMirror keeps local copies of images in a content addressed directory
+type ImageMirror struct {
+	Invoker *Invoker
+	//Dir the directory images and the index are stored in
+	Dir string
+	//Variants the image variants to download, ImageOriginal if empty
+	Variants []ImageVariant
+	//Index maps image URLs to file paths relative to Dir
+	Index map[string]string
+}
+
+//OpenImageMirror Creates an image mirror in dir loading its index if present
+func OpenImageMirror(invoker *Invoker, dir string, variants ...ImageVariant) (*ImageMirror, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ImageMirror{
+		Invoker:  invoker,
+		Dir:      dir,
+		Variants: variants,
+		Index:    make(map[string]string),
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, mirrorIndexFile))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &m.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+//Save writes the index to Dir
+func (m *ImageMirror) Save() error {
+	data, err := json.MarshalIndent(m.Index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(m.Dir, mirrorIndexFile+".tmp")
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(m.Dir, mirrorIndexFile))
+}
+
+//LocalPath returns the local file for an image URL if it has been mirrored
+func (m *ImageMirror) LocalPath(imageURL string) (string, bool) {
+	rel, ok := m.Index[imageURL]
+	if !ok {
+		return "", false
+	}
+
+	local := filepath.Join(m.Dir, rel)
+	if _, err := os.Stat(local); err != nil {
+		return "", false
+	}
+
+	return local, true
+}
+
+func (m *ImageMirror) variants() []ImageVariant {
+	if len(m.Variants) > 0 {
+		return m.Variants
+	}
+
+	return []ImageVariant{ImageOriginal}
+}
+
+//MirrorImage downloads the configured variants of an image skipping any
+//already present
+func (m *ImageMirror) MirrorImage(ctx context.Context, img *Image) error {
+	for _, variant := range m.variants() {
+		err := m.mirrorVariant(ctx, img, variant)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *ImageMirror) mirrorVariant(ctx context.Context, img *Image, variant ImageVariant) error {
+	imageURL := img.URL(variant)
+	if imageURL == "" {
+		return nil
+	}
+	if _, ok := m.LocalPath(imageURL); ok {
+		return nil
+	}
+
+	rel, err := m.download(ctx, img, variant)
+	if err != nil {
+		return err
+	}
+	m.Index[imageURL] = rel
+
+	return nil
+}
+
+//download stores an image under its sha256 returning the path relative to Dir
+func (m *ImageMirror) download(ctx context.Context, img *Image, variant ImageVariant) (string, error) {
+	tmp, err := ioutil.TempFile(m.Dir, "download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	err = m.Invoker.DownloadImage(ctx, img, variant, io.MultiWriter(tmp, hash))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	rel := filepath.Join(sum[:2], sum+imageExtension(img.URL(variant)))
+	local := filepath.Join(m.Dir, rel)
+	if _, err := os.Stat(local); err == nil {
+		return rel, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(local), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Rename(tmp.Name(), local)
+	if err != nil {
+		return "", err
+	}
+
+	return rel, nil
+}
+
+func imageExtension(imageURL string) string {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return ""
+	}
+
+	return path.Ext(u.Path)
+}
+
+//MirrorError lists the images Mirror couldn't download by image URL
+type MirrorError struct {
+	Errors map[string]error
+}
+
+func (e *MirrorError) Error() string {
+	urls := make([]string, 0, len(e.Errors))
+	for imageURL := range e.Errors {
+		urls = append(urls, imageURL)
+	}
+	sort.Strings(urls)
+
+	failures := make([]string, len(urls))
+	for idx, imageURL := range urls {
+		failures[idx] = fmt.Sprintf("%s: %v", imageURL, e.Errors[imageURL])
+	}
+
+	return fmt.Sprintf("mirroring %d images failed %s", len(urls), strings.Join(failures, ", "))
+}
+
+//Mirror downloads every Image found in v, v should be a pointer to a resource
+//such as *Game or a slice of resources. Images which fail are skipped and
+//reported in a *MirrorError, the index is saved either way
+func (m *ImageMirror) Mirror(ctx context.Context, v interface{}) error {
+	failed := make(map[string]error)
+	err := walkImages(reflect.ValueOf(v), func(img *Image) error {
+		for _, variant := range m.variants() {
+			err := m.mirrorVariant(ctx, img, variant)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				failed[img.URL(variant)] = err
+			}
+		}
+		return nil
+	})
+
+	saveErr := m.Save()
+	if err != nil {
+		return err
+	}
+	if saveErr != nil {
+		return saveErr
+	}
+
+	if len(failed) > 0 {
+		return &MirrorError{Errors: failed}
+	}
+
+	return nil
+}
+
+//Rewrite replaces every mirrored image URL found in v with its local path
+//v must be a pointer
+func (m *ImageMirror) Rewrite(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr {
+		return fmt.Errorf("rewrite needs a pointer got %T", v)
+	}
+
+	return walkImages(value, func(img *Image) error {
+		for _, variant := range ImageVariants {
+			if local, ok := m.LocalPath(img.URL(variant)); ok {
+				img.setURL(variant, local)
+			}
+		}
+		return nil
+	})
+}
+
+func (img *Image) setURL(variant ImageVariant, imageURL string) {
+	switch variant {
+	case ImageTiny:
+		img.TinyURL = imageURL
+	case ImageIcon:
+		img.IconURL = imageURL
+	case ImageThumb:
+		img.ThumbURL = imageURL
+	case ImageSmall:
+		img.SmallURL = imageURL
+	case ImageScreen:
+		img.ScreenURL = imageURL
+	case ImageMedium:
+		img.MediumURL = imageURL
+	case ImageScreenLarge:
+		img.ScreenLargeURL = imageURL
+	case ImageSuper:
+		img.SuperURL = imageURL
+	case ImageOriginal:
+		img.OriginalURL = imageURL
+	}
+}
+
+//walkImages calls fn for every Image reachable from v
+func walkImages(v reflect.Value, fn func(img *Image) error) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walkImages(v.Elem(), fn)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := walkImages(v.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if v.Type() == imageType {
+			if v.CanAddr() {
+				return fn(v.Addr().Interface().(*Image))
+			}
+			img := v.Interface().(Image)
+			return fn(&img)
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := walkImages(v.Field(i), fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
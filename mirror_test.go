@@ -0,0 +1,153 @@
+package gbomb
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+type ImageContentMock struct {
+	requests int
+	//missing file names are answered with a 404
+	missing map[string]bool
+}
+
+func (i *ImageContentMock) Do(req *http.Request) (*http.Response, error) {
+	i.requests++
+
+	//images that share a file name share content
+	parts := strings.Split(req.URL.Path, "/")
+	if i.missing[parts[len(parts)-1]] {
+		return &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			StatusCode: 404,
+			Status:     "404",
+		}, nil
+	}
+
+	return &http.Response{
+		Body:       ioutil.NopCloser(strings.NewReader(parts[len(parts)-1])),
+		StatusCode: 200,
+		Status:     "200",
+	}, nil
+}
+
+func TestImageMirror(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRequests := make(map[string]bool)
+	expectedRequests[game.Image.SuperURL] = true
+	for _, img := range game.Images {
+		expectedRequests[img.SuperURL] = true
+	}
+
+	dir := t.TempDir()
+	client := &ImageContentMock{}
+	invoker.client = client
+	mirror, err := OpenImageMirror(invoker, dir, ImageSuper)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = mirror.Mirror(context.Background(), game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.requests != len(expectedRequests) {
+		t.Errorf("made %d requests expected %d", client.requests, len(expectedRequests))
+	}
+
+	reopened, err := OpenImageMirror(invoker, dir, ImageSuper)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.requests = 0
+	err = reopened.Mirror(context.Background(), game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.requests != 0 {
+		t.Errorf("re-downloaded %d mirrored images", client.requests)
+	}
+
+	originalURL := game.Image.OriginalURL
+	err = reopened.Rewrite(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(game.Image.SuperURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "2946176-smobox.jpg" {
+		t.Errorf("invalid mirrored content %s", data)
+	}
+
+	if game.Image.OriginalURL != originalURL {
+		t.Errorf("rewrote variant that was not mirrored")
+	}
+
+	if _, err := os.Stat(game.Images[0].SuperURL); err != nil {
+		t.Errorf("images were not rewritten %v", err)
+	}
+}
+
+func TestImageMirrorMissingImage(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missingURL := game.Images[0].SuperURL
+	parts := strings.Split(missingURL, "/")
+
+	dir := t.TempDir()
+	client := &ImageContentMock{missing: map[string]bool{parts[len(parts)-1]: true}}
+	invoker.client = client
+	mirror, err := OpenImageMirror(invoker, dir, ImageSuper)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = mirror.Mirror(context.Background(), game)
+	mirrorErr, ok := err.(*MirrorError)
+	if !ok {
+		t.Fatalf("invalid error %v expected *MirrorError", err)
+	}
+	if len(mirrorErr.Errors) != 1 || mirrorErr.Errors[missingURL] == nil {
+		t.Errorf("invalid failed images %v", mirrorErr)
+	}
+
+	//images after the missing one are still mirrored and indexed
+	reopened, err := OpenImageMirror(invoker, dir, ImageSuper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reopened.LocalPath(game.Image.SuperURL); !ok {
+		t.Errorf("mirrored image missing from the saved index")
+	}
+	last := game.Images[len(game.Images)-1].SuperURL
+	if _, ok := reopened.LocalPath(last); !ok && last != missingURL {
+		t.Errorf("images after the failure were not mirrored")
+	}
+
+	client.requests = 0
+	reopened.Mirror(context.Background(), game)
+	if client.requests != 1 {
+		t.Errorf("made %d requests expected only the missing image", client.requests)
+	}
+}
@@ -0,0 +1,292 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+//OPML an OPML 2.0 subscription list
+type OPML struct {
+	XMLName  xml.Name      `xml:"opml"`
+	Version  string        `xml:"version,attr"`
+	Head     OPMLHead      `xml:"head"`
+	Outlines []OPMLOutline `xml:"body>outline"`
+}
+
+//OPMLHead OPML document metadata
+type OPMLHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+//OPMLOutline an OPML outline, feeds have an XMLURL while folders only group
+//their child outlines
+type OPMLOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []OPMLOutline `xml:"outline"`
+}
+
+//Feeds returns the outlines with a feed URL including those inside folders
+func (o *OPML) Feeds() []OPMLOutline {
+	var result []OPMLOutline
+	var walk func(outlines []OPMLOutline)
+	walk = func(outlines []OPMLOutline) {
+		for _, outline := range outlines {
+			if outline.XMLURL != "" {
+				result = append(result, outline)
+			}
+			walk(outline.Outlines)
+		}
+	}
+	walk(o.Outlines)
+
+	return result
+}
+
+//Encode writes the document with an XML header to w
+func (o *OPML) Encode(w io.Writer) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(o)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+//ParseOPML reads an OPML document
+func ParseOPML(r io.Reader) (*OPML, error) {
+	var result OPML
+	err := xml.NewDecoder(r).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+//OPMLOptions options for ExportOPML
+type OPMLOptions struct {
+	//Title of the document, "Giant Bomb Podcasts" if empty
+	Title string
+	//Feeds to export, every feed in the invoker's catalogue if empty
+	Feeds []PodcastFeed
+	//Channels already fetched channels by feed id, their titles and links are
+	//used without requesting the feed again
+	Channels map[string]*RSSChannel
+	//CatalogueTitles uses the catalogue's titles for feeds missing from
+	//Channels instead of requesting them, otherwise each is a rate limited
+	//request so with the default limit exporting takes around 30 seconds per
+	//feed
+	CatalogueTitles bool
+	//IncludeAPIKey adds the API key to the URLs of feeds on the API host so
+	//other clients can fetch premium feeds, the document must then be kept
+	//private
+	IncludeAPIKey bool
+}
+
+//FeedTitleError lists the feeds ExportOPML couldn't fetch titles for by id
+type FeedTitleError struct {
+	Errors map[string]error
+}
+
+func (f *FeedTitleError) Error() string {
+	ids := make([]string, 0, len(f.Errors))
+	for id := range f.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	failures := make([]string, len(ids))
+	for idx, id := range ids {
+		failures[idx] = fmt.Sprintf("%s: %v", id, f.Errors[id])
+	}
+
+	return fmt.Sprintf("fetching feed titles failed %s", strings.Join(failures, ", "))
+}
+
+//ExportOPML builds an OPML document of podcast feeds titled with each
+//channel's title, feeds missing from OPMLOptions.Channels are requested one
+//rate limited request at a time. Feeds whose titles can't be fetched keep the
+//catalogue's title and are reported in a *FeedTitleError returned along with
+//the document
+func (i *Invoker) ExportOPML(ctx context.Context, opts *OPMLOptions) (*OPML, error) {
+	if opts == nil {
+		opts = &OPMLOptions{}
+	}
+
+	feeds := opts.Feeds
+	if len(feeds) == 0 {
+		feeds = i.ListPodcastFeeds()
+	}
+
+	result := &OPML{
+		Version: "2.0",
+		Head: OPMLHead{
+			Title:       opts.Title,
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+	if result.Head.Title == "" {
+		result.Head.Title = "Giant Bomb Podcasts"
+	}
+
+	failed := make(map[string]error)
+	for _, feed := range feeds {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		feedURL := feed.URL(i.Endpoint)
+		if opts.IncludeAPIKey {
+			req, err := i.newPodcastRequest(ctx, "GET", feedURL)
+			if err != nil {
+				return nil, err
+			}
+			feedURL = req.URL.String()
+		}
+
+		outline := OPMLOutline{Type: "rss", XMLURL: feedURL}
+		if channel, ok := opts.Channels[feed.ID]; ok {
+			outline.Text = channel.Title
+			outline.HTMLURL = channel.Link
+		} else if !opts.CatalogueTitles {
+			title, err := i.feedTitle(ctx, feed)
+			if err != nil {
+				failed[feed.ID] = err
+			}
+			outline.Text = title
+		}
+		if outline.Text == "" {
+			outline.Text = feed.Title
+		}
+		if outline.Text == "" {
+			outline.Text = feed.ID
+		}
+		outline.Title = outline.Text
+
+		result.Outlines = append(result.Outlines, outline)
+	}
+
+	if len(failed) > 0 {
+		return result, &FeedTitleError{Errors: failed}
+	}
+
+	return result, nil
+}
+
+//feedTitle reads a feed up to its channel title
+func (i *Invoker) feedTitle(ctx context.Context, feed PodcastFeed) (string, error) {
+	decoder, err := i.OpenPodcastFeed(ctx, feed)
+	if err != nil {
+		return "", err
+	}
+	defer decoder.Close()
+
+	//the title comes before the first entry
+	decoder.Limit = 1
+	_, err = decoder.Next()
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return decoder.Title, nil
+}
+
+//OPMLSubscription a feed read from an OPML document
+type OPMLSubscription struct {
+	Title string
+	URL   string
+	//FeedID the id to pass to GetPodcasts, empty if the URL isn't a giant
+	//bomb feed
+	FeedID string
+	//Known false when the feed isn't in the invoker's catalogue
+	Known bool
+}
+
+//ImportOPML reads the feeds of an OPML document mapping their URLs to feed
+//ids, feeds not in the catalogue are flagged as unknown
+func (i *Invoker) ImportOPML(r io.Reader) ([]OPMLSubscription, error) {
+	doc, err := ParseOPML(r)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]string)
+	for _, feed := range i.ListPodcastFeeds() {
+		if key, err := feedURLKey(feed.URL(i.Endpoint)); err == nil {
+			known[key] = feed.ID
+		}
+	}
+
+	var result []OPMLSubscription
+	for _, outline := range doc.Feeds() {
+		sub := OPMLSubscription{Title: outline.Title, URL: outline.XMLURL}
+		if sub.Title == "" {
+			sub.Title = outline.Text
+		}
+
+		key, err := feedURLKey(outline.XMLURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid feed URL %q: %v", outline.XMLURL, err)
+		}
+		sub.FeedID, sub.Known = known[key]
+		if !sub.Known {
+			sub.FeedID = i.podcastSlug(outline.XMLURL)
+		}
+
+		result = append(result, sub)
+	}
+
+	return result, nil
+}
+
+//feedURLKey reduces a feed URL to its host and path so links differing only
+//by scheme, www, trailing slash or query e.g. an API key match
+func feedURLKey(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	return host + "/" + strings.Trim(u.Path, "/"), nil
+}
+
+//podcastSlug returns {slug} for an endpoint/podcast-xml/{slug}/ URL which
+//GetPodcasts treats as a feed id
+func (i *Invoker) podcastSlug(link string) string {
+	key, err := feedURLKey(link)
+	if err != nil {
+		return ""
+	}
+	endpoint, err := feedURLKey(i.Endpoint)
+	if err != nil {
+		return ""
+	}
+
+	prefix := strings.TrimSuffix(endpoint, "/") + "/podcast-xml/"
+	slug := strings.TrimPrefix(key, prefix)
+	if slug == key || slug == "" || strings.Contains(slug, "/") {
+		return ""
+	}
+
+	return slug
+}
@@ -0,0 +1,171 @@
+package gbomb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportOPML(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &RouteMock{routes: map[string]string{
+		"https://www.giantbomb.com/feeds/podcast/?api_key=coolbeans":         "test_data/bombcast_feed.xml",
+		"https://www.giantbomb.com/podcast-xml/beastcast/?api_key=coolbeans": "test_data/beastcast_feed.xml",
+	}}
+	//fetched titles replace the catalogue's
+	invoker.RegisterPodcastFeed(PodcastFeed{ID: "beastcast", Title: "Beastcast", Path: "podcast-xml/beastcast/"})
+
+	doc, err := invoker.ExportOPML(context.Background(), &OPMLOptions{
+		Channels: map[string]*RSSChannel{
+			"premium": {Title: "Premium Podcasts", Link: "https://www.giantbomb.com/premium/"},
+		},
+	})
+	titleErr, ok := err.(*FeedTitleError)
+	if !ok {
+		t.Fatalf("invalid error %v expected *FeedTitleError", err)
+	}
+	if len(titleErr.Errors) != 2 || titleErr.Errors["bombin-the-am"] == nil || titleErr.Errors["giant-bomb-presents"] == nil {
+		t.Errorf("invalid failed feeds %v", titleErr)
+	}
+
+	expected := []OPMLOutline{
+		{Text: "Giant Beastcast", XMLURL: "https://www.giantbomb.com/podcast-xml/beastcast/"},
+		{Text: "Giant Bombcast", XMLURL: "https://www.giantbomb.com/feeds/podcast/"},
+		{Text: "Bombin' the A.M.", XMLURL: "https://www.giantbomb.com/podcast-xml/bombin-the-am/"},
+		{Text: "Giant Bomb Presents", XMLURL: "https://www.giantbomb.com/podcast-xml/giant-bomb-presents/"},
+		{
+			Text: "Premium Podcasts", XMLURL: "https://www.giantbomb.com/podcast-xml/premium/",
+			HTMLURL: "https://www.giantbomb.com/premium/",
+		},
+	}
+	if len(doc.Outlines) != len(expected) {
+		t.Fatalf("invalid length read %d expected %d", len(doc.Outlines), len(expected))
+	}
+	for idx, outline := range doc.Outlines {
+		expected[idx].Title = expected[idx].Text
+		expected[idx].Type = "rss"
+		if outline.Text != expected[idx].Text || outline.Title != expected[idx].Title ||
+			outline.Type != expected[idx].Type || outline.XMLURL != expected[idx].XMLURL ||
+			outline.HTMLURL != expected[idx].HTMLURL {
+			t.Errorf("invalid outline %+v expected %+v", outline, expected[idx])
+		}
+	}
+
+	var buf bytes.Buffer
+	err = doc.Encode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<opml version="2.0">`,
+		`<title>Giant Bomb Podcasts</title>`,
+		`<outline text="Giant Bombcast" title="Giant Bombcast" type="rss" xmlUrl="https://www.giantbomb.com/feeds/podcast/"></outline>`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("opml missing %s", expected)
+		}
+	}
+	if strings.Contains(out, "coolbeans") {
+		t.Errorf("api key exported without IncludeAPIKey")
+	}
+
+	//with CatalogueTitles nothing is requested
+	invoker.client = &RouteMock{}
+	doc, err = invoker.ExportOPML(context.Background(), &OPMLOptions{CatalogueTitles: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Outlines[0].Text != "Beastcast" || doc.Outlines[1].Text != "Giant Bombcast" {
+		t.Errorf("catalogue titles not used %+v", doc.Outlines[:2])
+	}
+
+	doc, err = invoker.ExportOPML(context.Background(), &OPMLOptions{
+		Feeds:         []PodcastFeed{FeedPremium},
+		Channels:      map[string]*RSSChannel{"premium": {Title: "Premium"}},
+		IncludeAPIKey: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Outlines[0].XMLURL != "https://www.giantbomb.com/podcast-xml/premium/?api_key=coolbeans" {
+		t.Errorf("invalid url %s", doc.Outlines[0].XMLURL)
+	}
+}
+
+func TestImportOPML(t *testing.T) {
+	const opml = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>subscriptions</title></head>
+  <body>
+    <outline text="Giant Bombcast" type="rss" xmlUrl="http://giantbomb.com/feeds/podcast"/>
+    <outline text="Games">
+      <outline text="Beastcast" title="Giant Beastcast" type="rss" xmlUrl="https://www.giantbomb.com/podcast-xml/beastcast/?api_key=secret"/>
+      <outline text="Unfinished Business" type="rss" xmlUrl="https://www.giantbomb.com/podcast-xml/unfinished-business/"/>
+    </outline>
+    <outline text="Elsewhere" type="rss" xmlUrl="https://example.com/feed.xml"/>
+  </body>
+</opml>`
+
+	invoker := createTestInvoker()
+	subs, err := invoker.ImportOPML(strings.NewReader(opml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []OPMLSubscription{
+		{Title: "Giant Bombcast", URL: "http://giantbomb.com/feeds/podcast", FeedID: "bombcast", Known: true},
+		{
+			Title: "Giant Beastcast", URL: "https://www.giantbomb.com/podcast-xml/beastcast/?api_key=secret",
+			FeedID: "beastcast", Known: true,
+		},
+		{
+			Title: "Unfinished Business", URL: "https://www.giantbomb.com/podcast-xml/unfinished-business/",
+			FeedID: "unfinished-business",
+		},
+		{Title: "Elsewhere", URL: "https://example.com/feed.xml"},
+	}
+	if len(subs) != len(expected) {
+		t.Fatalf("invalid length read %d expected %d", len(subs), len(expected))
+	}
+	for idx, sub := range subs {
+		if sub != expected[idx] {
+			t.Errorf("invalid subscription %+v expected %+v", sub, expected[idx])
+		}
+	}
+
+	_, err = invoker.ImportOPML(strings.NewReader("<opml><body>"))
+	if err == nil {
+		t.Errorf("truncated document accepted")
+	}
+}
+
+func TestOPMLRoundTrip(t *testing.T) {
+	invoker := createTestInvoker()
+	channels := make(map[string]*RSSChannel)
+	for _, feed := range invoker.ListPodcastFeeds() {
+		channels[feed.ID] = &RSSChannel{Title: feed.Title}
+	}
+
+	doc, err := invoker.ExportOPML(context.Background(), &OPMLOptions{Channels: channels})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = doc.Encode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subs, err := invoker.ImportOPML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx, feed := range invoker.ListPodcastFeeds() {
+		if !subs[idx].Known || subs[idx].FeedID != feed.ID || subs[idx].Title != feed.Title {
+			t.Errorf("invalid subscription %+v for %s", subs[idx], feed.ID)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//DefaultPollInterval how often Run polls when Interval is unset
+const DefaultPollInterval = 15 * time.Minute
+
+//FeedPoller polls a podcast feed with conditional requests emitting entries
+//it has not seen before, it is not safe to call Poll while Run is active
+type FeedPoller struct {
+	Invoker *Invoker
+	Feed    PodcastFeed
+	//Interval between polls in Run, DefaultPollInterval if unset
+	Interval time.Duration
+	//Seen GUIDs of entries already emitted
+	Seen map[string]bool
+	//ETag and LastModified of the last response sent back as If-None-Match and
+	//If-Modified-Since
+	ETag         string
+	LastModified string
+	//OnError called when a poll fails during Run, polling carries on
+	OnError func(err error)
+}
+
+//NewFeedPoller Creates a poller for feed
+func NewFeedPoller(invoker *Invoker, feed PodcastFeed, interval time.Duration) *FeedPoller {
+	return &FeedPoller{
+		Invoker:  invoker,
+		Feed:     feed,
+		Interval: interval,
+		Seen:     make(map[string]bool),
+	}
+}
+
+//entryKey identifies an entry by GUID falling back to its link
+func entryKey(entry *RSSFeedEntry) string {
+	if entry.GUID != "" {
+		return entry.GUID
+	}
+
+	return entry.Link
+}
+
+//Poll fetches the feed once returning unseen entries oldest first, nothing
+//is returned when the server reports the feed has not been modified
+func (p *FeedPoller) Poll(ctx context.Context) ([]RSSFeedEntry, error) {
+	result, err := p.poll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range result.entries {
+		p.Seen[entryKey(&result.entries[idx])] = true
+	}
+	p.ETag, p.LastModified = result.etag, result.lastModified
+
+	return result.entries, nil
+}
+
+//feedPoll the outcome of a poll, it is only recorded on the poller once its
+//entries have been handed over so none are lost
+type feedPoll struct {
+	entries      []RSSFeedEntry
+	etag         string
+	lastModified string
+}
+
+func (p *FeedPoller) poll(ctx context.Context) (*feedPoll, error) {
+	req, err := p.Invoker.newFeedRequest(ctx, p.Feed)
+	if err != nil {
+		return nil, err
+	}
+	if p.ETag != "" {
+		req.Header.Set("If-None-Match", p.ETag)
+	}
+	if p.LastModified != "" {
+		req.Header.Set("If-Modified-Since", p.LastModified)
+	}
+
+	err = p.Invoker.requestLimiter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.Invoker.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusNotModified:
+		return &feedPoll{etag: p.ETag, lastModified: p.LastModified}, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("polling %s returned %s", p.Feed.ID, res.Status)
+	}
+
+	channel, err := decodeFeed(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &feedPoll{
+		etag:         res.Header.Get("ETag"),
+		lastModified: res.Header.Get("Last-Modified"),
+	}
+	for idx := len(channel.Entries) - 1; idx >= 0; idx-- {
+		entry := channel.Entries[idx]
+		if p.Seen[entryKey(&entry)] {
+			continue
+		}
+		result.entries = append(result.entries, entry)
+	}
+
+	return result, nil
+}
+
+//Prime polls once marking every current entry as seen so Run only emits
+//entries published afterwards
+func (p *FeedPoller) Prime(ctx context.Context) error {
+	_, err := p.Poll(ctx)
+	return err
+}
+
+//Run polls immediately then every Interval sending new entries on the
+//returned channel which is closed once ctx is done, entries are only marked
+//seen once they have been received
+func (p *FeedPoller) Run(ctx context.Context) <-chan RSSFeedEntry {
+	out := make(chan RSSFeedEntry)
+
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := p.poll(ctx)
+			if err != nil && ctx.Err() == nil && p.OnError != nil {
+				p.OnError(err)
+			}
+
+			if result != nil {
+				for _, entry := range result.entries {
+					select {
+					case out <- entry:
+						p.Seen[entryKey(&entry)] = true
+					case <-ctx.Done():
+						return
+					}
+				}
+				p.ETag, p.LastModified = result.etag, result.lastModified
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,164 @@
+package gbomb
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//ConditionalMock serves a feed honouring If-None-Match
+type ConditionalMock struct {
+	mu       sync.Mutex
+	feed     string
+	etag     string
+	requests int
+	matched  int
+}
+
+func (c *ConditionalMock) set(feed, etag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.feed, c.etag = feed, etag
+}
+
+func (c *ConditionalMock) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests++
+	res := &http.Response{Header: make(http.Header)}
+	res.Header.Set("ETag", c.etag)
+
+	if req.Header.Get("If-None-Match") == c.etag {
+		c.matched++
+		res.StatusCode = http.StatusNotModified
+		res.Status = http.StatusText(http.StatusNotModified)
+		res.Body = ioutil.NopCloser(strings.NewReader(""))
+		return res, nil
+	}
+
+	res.StatusCode = http.StatusOK
+	res.Status = http.StatusText(http.StatusOK)
+	res.Body = ioutil.NopCloser(strings.NewReader(c.feed))
+	return res, nil
+}
+
+//beastcastVersions returns the beastcast fixture without its newest entry and
+//the full fixture
+func beastcastVersions(t *testing.T) (string, string) {
+	data, err := ioutil.ReadFile("test_data/beastcast_feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	full := string(data)
+
+	start := strings.Index(full, "<item>")
+	end := strings.Index(full, "</item>") + len("</item>")
+
+	return full[:start] + full[end:], full
+}
+
+func TestFeedPollerPoll(t *testing.T) {
+	old, full := beastcastVersions(t)
+
+	client := &ConditionalMock{feed: old, etag: `"v1"`}
+	invoker := createTestInvoker()
+	invoker.client = client
+
+	poller := NewFeedPoller(invoker, FeedBeastcast, time.Minute)
+	err := poller.Prime(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(poller.Seen) != 2 || poller.ETag != `"v1"` {
+		t.Errorf("invalid primed state %d seen etag %s", len(poller.Seen), poller.ETag)
+	}
+
+	entries, err := poller.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 || client.matched != 1 {
+		t.Errorf("unmodified feed returned %d entries with %d conditional hits", len(entries), client.matched)
+	}
+
+	client.set(full, `"v2"`)
+	entries, err = poller.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].GUID != "1600-3250" {
+		t.Errorf("invalid new entries %v", entries)
+	}
+}
+
+func TestFeedPollerRun(t *testing.T) {
+	old, full := beastcastVersions(t)
+
+	client := &ConditionalMock{feed: old, etag: `"v1"`}
+	invoker := createTestInvoker()
+	invoker.client = client
+
+	poller := NewFeedPoller(invoker, FeedBeastcast, 10*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entries := poller.Run(ctx)
+
+	var guids []string
+	for entry := range entries {
+		guids = append(guids, entry.GUID)
+		if len(guids) == 2 {
+			client.set(full, `"v2"`)
+		}
+		if len(guids) == 3 {
+			cancel()
+		}
+	}
+
+	expected := []string{"1600-3230", "1600-3241", "1600-3250"}
+	if strings.Join(guids, " ") != strings.Join(expected, " ") {
+		t.Errorf("emitted %v expected %v", guids, expected)
+	}
+}
+
+func TestFeedPollerRunCancelled(t *testing.T) {
+	_, full := beastcastVersions(t)
+
+	client := &ConditionalMock{feed: full, etag: `"v1"`}
+	invoker := createTestInvoker()
+	invoker.client = client
+
+	//an unset interval falls back to the default
+	poller := NewFeedPoller(invoker, FeedBeastcast, 0)
+
+	received := make(map[string]int)
+	ctx, cancel := context.WithCancel(context.Background())
+	entries := poller.Run(ctx)
+	first := <-entries
+	received[first.GUID]++
+	cancel()
+	for entry := range entries {
+		received[entry.GUID]++
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for entry := range poller.Run(ctx) {
+		received[entry.GUID]++
+		if len(received) == 3 {
+			cancel()
+		}
+	}
+
+	for _, guid := range []string{"1600-3230", "1600-3241", "1600-3250"} {
+		if received[guid] != 1 {
+			t.Errorf("%s received %d times expected %d", guid, received[guid], 1)
+		}
+	}
+}
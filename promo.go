@@ -0,0 +1,112 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+//Promo a giant bomb API promo
+type Promo struct {
+	APIDetailURL string `json:"api_detail_url"`
+	DateAdded    Date   `json:"date_added"`
+	Deck         string `json:"deck"`
+	GUID         GUID   `json:"guid"`
+	ID           int    `json:"id"`
+	Image        Image  `json:"image"`
+	Link         string `json:"link"`
+	Name         string `json:"name"`
+	ResourceType string `json:"resource_type"`
+	User         string `json:"user"`
+}
+
+//PromosResponse promos response from giant bomb API
+type PromosResponse struct {
+	ResponsePage
+	Results []Promo `json:"results"`
+}
+
+//Path returns promos path
+func (p *PromosResponse) Path() (string, map[string]string) {
+	return "api/promos", make(map[string]string)
+}
+
+//Parse parse
+func (p *PromosResponse) Parse(data []byte) error {
+	var tmp PromosResponse
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	p.ResponsePage = tmp.ResponsePage
+	p.Results = tmp.Results
+
+	return nil
+}
+
+//GetPromos returns a page of promos
+func (i *Invoker) GetPromos(ctx context.Context, offset int) (*PromosResponse, error) {
+	result := &PromosResponse{}
+	result.Offset = offset
+
+	body, err := i.GetContext(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type promoResponseInternal struct {
+	ResponsePage
+	Results     *Promo `json:"results"`
+	targetPromo GUID
+}
+
+//Path returns promo path
+func (p *promoResponseInternal) Path() (string, map[string]string) {
+	return fmt.Sprintf("api/promo/%s", p.targetPromo), make(map[string]string)
+}
+
+//Parse parse
+func (p *promoResponseInternal) Parse(data []byte) error {
+	var tmp promoResponseInternal
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	p.ResponsePage = tmp.ResponsePage
+	p.Results = tmp.Results
+
+	return nil
+}
+
+//GetPromo returns a given promo
+func (i *Invoker) GetPromo(ctx context.Context, promoID GUID) (*Promo, error) {
+	if err := i.checkGUID(promoID, "promo"); err != nil {
+		return nil, err
+	}
+
+	result := &promoResponseInternal{}
+
+	result.targetPromo = promoID
+
+	body, err := i.GetContext(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Results, nil
+}
@@ -0,0 +1,43 @@
+package gbomb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetPromos(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &FileMock{
+		expectedURL: "https://www.giantbomb.com/api/promos?api_key=coolbeans&format=json&offset=0",
+		file:        "test_data/promos.json",
+	}
+	result, err := invoker.GetPromos(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Results) != 2 {
+		t.Fatalf(
+			"invalid number of promos pulled %d expected %d",
+			len(result.Results), 2,
+		)
+	}
+
+	if result.Results[0].ResourceType != "podcast" {
+		t.Errorf(
+			"invalid resource type %s expected %s",
+			result.Results[0].ResourceType, "podcast",
+		)
+	}
+
+	if result.Results[1].DateAdded.String() != "2021-02-09 16:30:00" {
+		t.Errorf(
+			"did not parse date added correctly was %s expected %s",
+			result.Results[1].DateAdded.String(), "2021-02-09 16:30:00",
+		)
+	}
+
+	if result.Complete() {
+		t.Errorf("was complete early")
+	}
+}
@@ -0,0 +1,326 @@
+//Package proxy serves giant bomb podcast and video feeds over HTTP keeping the
+//API key on the server
+//
+//	/podcasts/{feed}.xml  a podcast feed from the invoker's catalogue
+//	/videos/{show}.xml    a video show's listings as a podcast feed
+//	/download/{id}        streams an enclosure the server has handed out
+package proxy
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sardap/gbomb"
+)
+
+//DefaultCacheTTL how long upstream feeds are reused when CacheTTL is unset
+const DefaultCacheTTL = 15 * time.Minute
+
+//forwardedHeaders response headers passed from a download to the client
+var forwardedHeaders = []string{
+	"Accept-Ranges", "Content-Length", "Content-Range", "Content-Type", "Last-Modified", "ETag",
+}
+
+type cachedFeed struct {
+	body    []byte
+	expires time.Time
+}
+
+//Server an http.Handler serving feeds with enclosures rewritten through its
+//download endpoint
+type Server struct {
+	Invoker *gbomb.Invoker
+	//BaseURL the server's public URL enclosures are rewritten to
+	//e.g. http://localhost:8080
+	BaseURL string
+	//CacheTTL how long upstream feeds are reused, DefaultCacheTTL if unset
+	CacheTTL time.Duration
+	//Policy picks the rendition of video enclosures
+	Policy gbomb.QualityPolicy
+	//Secret signs download ids so only enclosures the server handed out are
+	//proxied, New sets a random one so set it to keep links valid across
+	//restarts
+	Secret []byte
+	//ErrorLog logs upstream failures, clients only see a generic error, the
+	//log package's standard logger if nil
+	ErrorLog *log.Logger
+
+	mux   *http.ServeMux
+	mu    sync.Mutex
+	cache map[string]cachedFeed
+	now   func() time.Time
+}
+
+//New Creates a server for invoker reachable at baseURL
+func New(invoker *gbomb.Invoker, baseURL string) *Server {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+
+	s := &Server{
+		Invoker: invoker,
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Secret:  secret,
+		cache:   make(map[string]cachedFeed),
+		now:     time.Now,
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("/podcasts/", s.servePodcast)
+	s.mux.HandleFunc("/videos/", s.serveVideos)
+	s.mux.HandleFunc("/download/", s.serveDownload)
+
+	return s
+}
+
+//ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+//feedName returns {name} from /prefix/{name}.xml
+func feedName(path, prefix string) (string, bool) {
+	name := strings.TrimPrefix(path, prefix)
+	if !strings.HasSuffix(name, ".xml") || strings.Contains(name, "/") {
+		return "", false
+	}
+	name = strings.TrimSuffix(name, ".xml")
+
+	return name, name != ""
+}
+
+//logf logs an upstream failure
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+//upstreamError logs err and answers with a generic error as upstream errors
+//include request URLs carrying the API key
+func (s *Server) upstreamError(w http.ResponseWriter, r *http.Request, err error) {
+	s.logf("proxy: %s %s: %v", r.Method, r.URL.Path, err)
+	http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+}
+
+func (s *Server) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+//downloadURL returns the proxy URL serving upstream, its id is the upstream
+//URL without the API key signed with Secret
+func (s *Server) downloadURL(upstream string) string {
+	if u, err := url.Parse(upstream); err == nil {
+		q := u.Query()
+		if _, ok := q["api_key"]; ok {
+			q.Del("api_key")
+			u.RawQuery = q.Encode()
+			upstream = u.String()
+		}
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(upstream))
+	return fmt.Sprintf("%s/download/%s.%s", s.BaseURL, payload, s.sign(payload))
+}
+
+//upstreamURL returns the URL a download id was signed for
+func (s *Server) upstreamURL(id string) (string, bool) {
+	idx := strings.LastIndex(id, ".")
+	if idx < 0 {
+		return "", false
+	}
+	payload, mac := id[:idx], id[idx+1:]
+	if !hmac.Equal([]byte(mac), []byte(s.sign(payload))) {
+		return "", false
+	}
+
+	upstream, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", false
+	}
+
+	return string(upstream), true
+}
+
+func (s *Server) cacheTTL() time.Duration {
+	if s.CacheTTL > 0 {
+		return s.CacheTTL
+	}
+
+	return DefaultCacheTTL
+}
+
+//cached returns the body stored for key rendering it with render when it is
+//missing or expired
+func (s *Server) cached(key string, render func() ([]byte, error)) ([]byte, error) {
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && s.now().Before(entry.expires) {
+		return entry.body, nil
+	}
+
+	body, err := render()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	for cachedKey, entry := range s.cache {
+		if !now.Before(entry.expires) {
+			delete(s.cache, cachedKey)
+		}
+	}
+	s.cache[key] = cachedFeed{body: body, expires: now.Add(s.cacheTTL())}
+	s.mu.Unlock()
+
+	return body, nil
+}
+
+func (s *Server) writeFeed(w http.ResponseWriter, r *http.Request, body []byte, err error) {
+	if err != nil {
+		s.upstreamError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+}
+
+func (s *Server) servePodcast(w http.ResponseWriter, r *http.Request) {
+	id, ok := feedName(r.URL.Path, "/podcasts/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if _, known := s.Invoker.PodcastFeed(id); !known {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := s.cached(r.URL.Path, func() ([]byte, error) {
+		channel, err := s.Invoker.GetPodcasts(id)
+		if err != nil {
+			return nil, err
+		}
+
+		channel.AtomLink = gbomb.AtomLink{
+			Href: s.BaseURL + r.URL.Path, Rel: "self", Type: "application/rss+xml",
+		}
+		for idx := range channel.Entries {
+			s.rewriteEntry(&channel.Entries[idx])
+		}
+
+		return render(channel)
+	})
+	s.writeFeed(w, r, body, err)
+}
+
+//rewriteEntry points an entry's media at the download endpoint
+func (s *Server) rewriteEntry(entry *gbomb.RSSFeedEntry) {
+	if entry.Enclosure.URL != "" {
+		entry.Enclosure.URL = s.downloadURL(entry.Enclosure.URL)
+	}
+	for idx := range entry.Content {
+		if entry.Content[idx].URL != "" {
+			entry.Content[idx].URL = s.downloadURL(entry.Content[idx].URL)
+		}
+	}
+}
+
+func (s *Server) serveVideos(w http.ResponseWriter, r *http.Request) {
+	name, ok := feedName(r.URL.Path, "/videos/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	show, err := strconv.Atoi(name)
+	if err != nil || show <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := s.cached(r.URL.Path, func() ([]byte, error) {
+		videos, err := s.Invoker.GetVideosFiltered(r.Context(), 0, gbomb.VideoFilter{Show: show})
+		if err != nil {
+			return nil, err
+		}
+
+		channel := gbomb.NewVideosResponseFeed(videos, &gbomb.VideoFeedOptions{
+			SelfURL: s.BaseURL + r.URL.Path,
+			Policy:  s.Policy,
+			EnclosureURL: func(video *gbomb.VideoInfo, selection gbomb.Selection) string {
+				return s.downloadURL(selection.URL)
+			},
+		})
+
+		return render(channel)
+	})
+	s.writeFeed(w, r, body, err)
+}
+
+func render(channel *gbomb.RSSChannel) ([]byte, error) {
+	var buf strings.Builder
+	err := channel.WriteRSS(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(buf.String()), nil
+}
+
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request) {
+	upstream, ok := s.upstreamURL(strings.TrimPrefix(r.URL.Path, "/download/"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	res, err := s.Invoker.OpenDownload(r.Context(), r.Method, upstream, r.Header.Get("Range"))
+	if err != nil {
+		s.upstreamError(w, r, err)
+		return
+	}
+	defer res.Body.Close()
+
+	for _, header := range forwardedHeaders {
+		if value := res.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	if res.ContentLength >= 0 && w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.FormatInt(res.ContentLength, 10))
+	}
+	w.WriteHeader(res.StatusCode)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+	io.Copy(w, res.Body)
+}
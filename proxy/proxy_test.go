@@ -0,0 +1,343 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sardap/gbomb"
+	"golang.org/x/time/rate"
+)
+
+const audio = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+//podtracPath the path of the bombcast feed's first enclosure
+const podtracPath = "/redirect.mp3/www.giantbomb.com/podcasts/download/3246/vf_bc_672_020921_62-02-09-2021-2391958662.mp3"
+
+//UpstreamMock serves the premium and bombcast feeds, a page of videos and
+//audio files, only requests to the API host may carry the key
+type UpstreamMock struct {
+	mu       sync.Mutex
+	requests map[string]int
+	methods  []string
+	feed     string
+	bombcast string
+	videos   string
+	//down fails every request like an unreachable host
+	down bool
+}
+
+func (u *UpstreamMock) Do(req *http.Request) (*http.Response, error) {
+	u.mu.Lock()
+	u.requests[req.URL.Path]++
+	u.methods = append(u.methods, req.Method)
+	u.mu.Unlock()
+
+	if u.down {
+		return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: errors.New("connection refused")}
+	}
+
+	q := req.URL.Query()
+	if req.URL.Host != "www.giantbomb.com" {
+		if _, ok := q["api_key"]; ok {
+			return nil, fmt.Errorf("api key sent to %s", req.URL.Host)
+		}
+	} else if q.Get("api_key") != "coolbeans" {
+		return nil, fmt.Errorf("missing api key %s", req.URL)
+	}
+
+	res := &http.Response{
+		Header:     make(http.Header),
+		StatusCode: http.StatusOK,
+		Status:     http.StatusText(http.StatusOK),
+	}
+
+	body := ""
+	switch req.URL.Path {
+	case "/podcast-xml/premium/":
+		body = u.feed
+	case "/feeds/podcast/":
+		body = u.bombcast
+	case "/api/videos":
+		if q.Get("filter") != "video_show:3" {
+			return nil, fmt.Errorf("invalid filter %s", req.URL)
+		}
+		body = u.videos
+	case "/podcasts/download/3249/bta_021121.mp3", "/video/1_hd.mp4", podtracPath:
+		body = audio
+		res.Header.Set("Content-Type", "audio/mpeg")
+		res.Header.Set("Accept-Ranges", "bytes")
+		if req.Header.Get("Range") == "bytes=10-19" {
+			body = audio[10:20]
+			res.StatusCode = http.StatusPartialContent
+			res.Status = http.StatusText(http.StatusPartialContent)
+			res.Header.Set("Content-Range", fmt.Sprintf("bytes 10-19/%d", len(audio)))
+		}
+	default:
+		return nil, fmt.Errorf("unexpected URL %s", req.URL)
+	}
+
+	res.Body = ioutil.NopCloser(strings.NewReader(body))
+	res.ContentLength = int64(len(body))
+
+	return res, nil
+}
+
+func createTestServer(t *testing.T) (*Server, *UpstreamMock) {
+	feed, err := ioutil.ReadFile("../test_data/premium_feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bombcast, err := ioutil.ReadFile("../test_data/bombcast_feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	videos, err := json.Marshal(gbomb.VideosResponse{
+		ResponsePage: gbomb.ResponsePage{Error: "OK", PageResults: 1, MaxResults: 1, StatusCode: 1},
+		Videos: []gbomb.VideoInfo{{
+			GUID: "2300-1", Name: "Quick Look: Cyber Shadow", Show: gbomb.VideoShow{Title: "Quick Look"},
+			HDURL: "https://www.giantbomb.com/video/1_hd.mp4",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &UpstreamMock{
+		requests: make(map[string]int),
+		//premium feeds carry the key in their enclosures
+		feed:     strings.Replace(string(feed), ".mp3\"", ".mp3?api_key=coolbeans\"", -1),
+		bombcast: string(bombcast),
+		videos:   string(videos),
+	}
+
+	invoker := gbomb.CreateInvoker("https://www.giantbomb.com", "coolbeans")
+	invoker.Limter = rate.NewLimiter(rate.Every(time.Duration(0)*time.Second), 1)
+	invoker.SetClient(client)
+
+	return New(invoker, "http://localhost:8080/"), client
+}
+
+func get(t *testing.T, handler http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func decode(t *testing.T, body string) (string, []gbomb.RSSFeedEntry) {
+	decoder := gbomb.NewFeedDecoder(strings.NewReader(body))
+	entries, err := decoder.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return decoder.Title, entries
+}
+
+func TestPodcastFeed(t *testing.T) {
+	server, client := createTestServer(t)
+
+	rec := get(t, server, "/podcasts/premium.xml", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("invalid status %d %s", rec.Code, rec.Body)
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "coolbeans") {
+		t.Errorf("api key served to the client")
+	}
+	if strings.Contains(body, "www.giantbomb.com/podcasts/download") {
+		t.Errorf("enclosure not rewritten")
+	}
+	if !strings.Contains(body, `<atom:link href="http://localhost:8080/podcasts/premium.xml" rel="self"`) {
+		t.Errorf("missing self link")
+	}
+
+	_, entries := decode(t, body)
+	if len(entries) != 2 {
+		t.Fatalf("invalid length read %d expected %d", len(entries), 2)
+	}
+	link := entries[0].Enclosure.URL
+	if !strings.HasPrefix(link, "http://localhost:8080/download/") {
+		t.Fatalf("invalid enclosure %s", link)
+	}
+
+	get(t, server, "/podcasts/premium.xml", nil)
+	if client.requests["/podcast-xml/premium/"] != 1 {
+		t.Errorf("feed requested %d times expected %d", client.requests["/podcast-xml/premium/"], 1)
+	}
+
+	server.now = func() time.Time { return time.Now().Add(DefaultCacheTTL) }
+	get(t, server, "/podcasts/premium.xml", nil)
+	if client.requests["/podcast-xml/premium/"] != 2 {
+		t.Errorf("expired feed not requested again")
+	}
+
+	path := strings.TrimPrefix(link, "http://localhost:8080")
+	rec = get(t, server, path, nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != audio {
+		t.Errorf("invalid download %d %q", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Content-Type") != "audio/mpeg" {
+		t.Errorf("invalid content type %s", rec.Header().Get("Content-Type"))
+	}
+
+	rec = get(t, server, path, http.Header{"Range": []string{"bytes=10-19"}})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != audio[10:20] {
+		t.Errorf("invalid range %d %q", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Content-Range") != "bytes 10-19/36" {
+		t.Errorf("invalid content range %s", rec.Header().Get("Content-Range"))
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest("HEAD", path, nil))
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("invalid HEAD response %d %q", rec.Code, rec.Body)
+	}
+	if method := client.methods[len(client.methods)-1]; method != "HEAD" {
+		t.Errorf("HEAD proxied upstream as %s", method)
+	}
+}
+
+func TestPodcastCDNDownload(t *testing.T) {
+	server, client := createTestServer(t)
+
+	rec := get(t, server, "/podcasts/bombcast.xml", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("invalid status %d %s", rec.Code, rec.Body)
+	}
+	_, entries := decode(t, rec.Body.String())
+	path := strings.TrimPrefix(entries[0].Enclosure.URL, "http://localhost:8080")
+
+	//a limiter that never allows a request fails any download waiting on it
+	server.Invoker.Limter = rate.NewLimiter(rate.Every(time.Hour), 0)
+	rec = get(t, server, path, http.Header{"Range": []string{"bytes=10-19"}})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != audio[10:20] {
+		t.Errorf("invalid range %d %q", rec.Code, rec.Body)
+	}
+	if client.requests[podtracPath] != 1 {
+		t.Errorf("enclosure requested %d times expected %d", client.requests[podtracPath], 1)
+	}
+}
+
+func TestDownloadIDs(t *testing.T) {
+	server, _ := createTestServer(t)
+	server.Secret = []byte("secret")
+
+	rec := get(t, server, "/podcasts/premium.xml", nil)
+	_, entries := decode(t, rec.Body.String())
+	path := strings.TrimPrefix(entries[0].Enclosure.URL, "http://localhost:8080")
+
+	//a restarted server with the same secret still serves the link
+	restarted, _ := createTestServer(t)
+	restarted.Secret = []byte("secret")
+	rec = get(t, restarted, path, nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != audio {
+		t.Errorf("invalid download after restart %d %q", rec.Code, rec.Body)
+	}
+
+	other, _ := createTestServer(t)
+	rec = get(t, other, path, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("link signed with another secret returned %d", rec.Code)
+	}
+
+	tampered := path[:len(path)-1] + "0"
+	if tampered == path {
+		tampered = path[:len(path)-1] + "1"
+	}
+	rec = get(t, server, tampered, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("tampered link returned %d", rec.Code)
+	}
+}
+
+func TestUpstreamFailure(t *testing.T) {
+	server, client := createTestServer(t)
+	var logged bytes.Buffer
+	server.ErrorLog = log.New(&logged, "", 0)
+
+	rec := get(t, server, "/podcasts/premium.xml", nil)
+	_, entries := decode(t, rec.Body.String())
+	download := strings.TrimPrefix(entries[0].Enclosure.URL, "http://localhost:8080")
+
+	client.down = true
+	server.now = func() time.Time { return time.Now().Add(DefaultCacheTTL) }
+	for _, path := range []string{"/podcasts/premium.xml", "/videos/3.xml", download} {
+		rec := get(t, server, path, nil)
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("%s returned %d expected %d", path, rec.Code, http.StatusBadGateway)
+		}
+		if strings.Contains(rec.Body.String(), "coolbeans") || strings.Contains(rec.Body.String(), "connection refused") {
+			t.Errorf("%s sent the upstream error to the client %q", path, rec.Body)
+		}
+	}
+
+	if strings.Count(logged.String(), "connection refused") != 3 {
+		t.Errorf("upstream errors not logged %q", logged.String())
+	}
+}
+
+func TestVideoFeed(t *testing.T) {
+	server, client := createTestServer(t)
+
+	rec := get(t, server, "/videos/3.xml", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("invalid status %d %s", rec.Code, rec.Body)
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "coolbeans") || strings.Contains(body, "1_hd.mp4") {
+		t.Errorf("upstream video url served to the client")
+	}
+
+	title, entries := decode(t, body)
+	if title != "Quick Look" || len(entries) != 1 {
+		t.Fatalf("invalid channel %s with %d entries", title, len(entries))
+	}
+
+	path := strings.TrimPrefix(entries[0].Enclosure.URL, "http://localhost:8080")
+	rec = get(t, server, path, nil)
+	if rec.Body.String() != audio {
+		t.Errorf("invalid download %q", rec.Body)
+	}
+	if client.requests["/video/1_hd.mp4"] != 1 {
+		t.Errorf("video requested %d times expected %d", client.requests["/video/1_hd.mp4"], 1)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	server, client := createTestServer(t)
+
+	for _, path := range []string{
+		"/podcasts/unknown.xml",
+		"/podcasts/premium",
+		"/videos/quick-look.xml",
+		"/download/deadbeef",
+		"/",
+	} {
+		rec := get(t, server, path, nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s returned %d expected %d", path, rec.Code, http.StatusNotFound)
+		}
+	}
+
+	if len(client.requests) != 0 {
+		t.Errorf("upstream requested %v", client.requests)
+	}
+}
@@ -0,0 +1,217 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//Quality a video rendition
+type Quality int
+
+//Video renditions, QualityLow to QualityHD are direct downloads from lowest
+//to highest
+const (
+	QualityLow Quality = iota
+	QualityHigh
+	QualityHD
+	//QualityDefault the unlabelled url field
+	QualityDefault
+	//QualityYouTube the youtube watch page, not a direct download
+	QualityYouTube
+)
+
+//String returns the quality as a string
+func (q Quality) String() string {
+	switch q {
+	case QualityLow:
+		return "low"
+	case QualityHigh:
+		return "high"
+	case QualityHD:
+		return "hd"
+	case QualityDefault:
+		return "default"
+	case QualityYouTube:
+		return "youtube"
+	}
+
+	return fmt.Sprintf("Quality(%d)", int(q))
+}
+
+//Downloadable returns if the quality is a direct file download
+func (q Quality) Downloadable() bool {
+	return q != QualityYouTube
+}
+
+//YouTubeURL returns the youtube watch page for the video or "" if it is not
+//on youtube
+func (v *VideoInfo) YouTubeURL() string {
+	if !v.OnYoutube() {
+		return ""
+	}
+
+	return "https://www.youtube.com/watch?v=" + v.YoutubeID
+}
+
+//QualityURL returns the URL for a quality or "" if there is none
+func (v *VideoInfo) QualityURL(quality Quality) string {
+	switch quality {
+	case QualityLow:
+		return v.LowURL
+	case QualityHigh:
+		return v.HighURL
+	case QualityHD:
+		return v.HDURL
+	case QualityDefault:
+		return v.URL
+	case QualityYouTube:
+		return v.YouTubeURL()
+	}
+
+	return ""
+}
+
+//QualityPolicy decides which rendition SelectURL picks
+type QualityPolicy int
+
+//Quality policies
+const (
+	//PreferHD picks the highest direct download falling back to youtube
+	PreferHD QualityPolicy = iota
+	//CapAtHigh picks the highest direct download that is not HD
+	CapAtHigh
+	//PreferSmallest picks the lowest direct download
+	PreferSmallest
+	//YouTubeOnly only picks the youtube watch page
+	YouTubeOnly
+)
+
+//String returns the policy as a string
+func (p QualityPolicy) String() string {
+	switch p {
+	case PreferHD:
+		return "prefer hd"
+	case CapAtHigh:
+		return "cap at high"
+	case PreferSmallest:
+		return "prefer smallest"
+	case YouTubeOnly:
+		return "youtube only"
+	}
+
+	return fmt.Sprintf("QualityPolicy(%d)", int(p))
+}
+
+//ParseQualityPolicy parses a policy from its String form ignoring case, dashes
+//and underscores may be used in place of spaces e.g. prefer-hd
+func ParseQualityPolicy(s string) (QualityPolicy, error) {
+	name := strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(s))
+	for _, policy := range []QualityPolicy{PreferHD, CapAtHigh, PreferSmallest, YouTubeOnly} {
+		if policy.String() == name {
+			return policy, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown quality policy %q", s)
+}
+
+//Order returns the renditions the policy accepts most preferred first
+func (p QualityPolicy) Order() []Quality {
+	switch p {
+	case PreferHD:
+		return []Quality{QualityHD, QualityHigh, QualityLow, QualityDefault, QualityYouTube}
+	case CapAtHigh:
+		return []Quality{QualityHigh, QualityLow, QualityDefault, QualityYouTube}
+	case PreferSmallest:
+		return []Quality{QualityLow, QualityHigh, QualityHD, QualityDefault, QualityYouTube}
+	case YouTubeOnly:
+		return []Quality{QualityYouTube}
+	}
+
+	return nil
+}
+
+//Selection the rendition SelectURL chose
+type Selection struct {
+	Quality Quality
+	URL     string
+	//Reason a human readable explanation of the choice
+	Reason string
+}
+
+//SelectURL picks a rendition according to policy explaining why
+func (v *VideoInfo) SelectURL(policy QualityPolicy) (Selection, error) {
+	order := policy.Order()
+	for idx, quality := range order {
+		url := v.QualityURL(quality)
+		if url == "" {
+			continue
+		}
+
+		reason := fmt.Sprintf("%s picked %s", policy, quality)
+		if idx > 0 {
+			reason = fmt.Sprintf(
+				"%s preferred %s but it is unavailable so picked %s",
+				policy, order[0], quality,
+			)
+		}
+
+		return Selection{Quality: quality, URL: url, Reason: reason}, nil
+	}
+
+	return Selection{}, fmt.Errorf("video %s has no rendition allowed by %s", v.GUID, policy)
+}
+
+//ProbeSize returns the size in bytes of a video rendition using a HEAD
+//request, -1 if the server does not report it
+func (i *Invoker) ProbeSize(ctx context.Context, video *VideoInfo, quality Quality) (int64, error) {
+	if !quality.Downloadable() {
+		return 0, fmt.Errorf("%s is not a direct download", quality)
+	}
+
+	url := video.QualityURL(quality)
+	if url == "" {
+		return 0, fmt.Errorf("video %s has no %s url", video.GUID, quality)
+	}
+
+	req, err := i.newDownloadRequest(ctx, "HEAD", url)
+	if err != nil {
+		return 0, err
+	}
+
+	err = i.requestLimiter(ctx)
+	if err != nil {
+		return 0, err
+	}
+	res, err := i.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("probing %s returned %s", url, res.Status)
+	}
+
+	return res.ContentLength, nil
+}
+
+//ProbeSizes returns the size of every available direct download rendition
+func (i *Invoker) ProbeSizes(ctx context.Context, video *VideoInfo) (map[Quality]int64, error) {
+	sizes := make(map[Quality]int64)
+	for _, quality := range []Quality{QualityLow, QualityHigh, QualityHD, QualityDefault} {
+		if video.QualityURL(quality) == "" {
+			continue
+		}
+
+		size, err := i.ProbeSize(ctx, video, quality)
+		if err != nil {
+			return nil, err
+		}
+		sizes[quality] = size
+	}
+
+	return sizes, nil
+}
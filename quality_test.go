@@ -0,0 +1,126 @@
+package gbomb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSelectURL(t *testing.T) {
+	video := &VideoInfo{
+		GUID:      "2300-16713",
+		LowURL:    "https://giantbomb.com/video_low.mp4",
+		HighURL:   "https://giantbomb.com/video_high.mp4",
+		HDURL:     "https://giantbomb.com/video_hd.mp4",
+		YoutubeID: "dQw4w9WgXcQ",
+	}
+
+	cases := map[QualityPolicy]Quality{
+		PreferHD:       QualityHD,
+		CapAtHigh:      QualityHigh,
+		PreferSmallest: QualityLow,
+		YouTubeOnly:    QualityYouTube,
+	}
+	for policy, expected := range cases {
+		selection, err := video.SelectURL(policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if selection.Quality != expected || selection.URL != video.QualityURL(expected) {
+			t.Errorf("%s selected %s expected %s", policy, selection.Quality, expected)
+		}
+	}
+
+	if video.QualityURL(QualityYouTube) != "https://www.youtube.com/watch?v=dQw4w9WgXcQ" {
+		t.Errorf("invalid youtube url %s", video.QualityURL(QualityYouTube))
+	}
+
+	premium := &VideoInfo{GUID: "2300-1", HDURL: "https://giantbomb.com/hd.mp4"}
+	selection, err := premium.SelectURL(PreferHD)
+	if err != nil || selection.Reason != "prefer hd picked hd" {
+		t.Errorf("invalid selection %v %v", selection, err)
+	}
+
+	if _, err := premium.SelectURL(CapAtHigh); err == nil {
+		t.Errorf("cap at high selected hd")
+	}
+
+	youtube := &VideoInfo{GUID: "2300-2", YoutubeID: "abc"}
+	selection, err = youtube.SelectURL(PreferSmallest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "prefer smallest preferred low but it is unavailable so picked youtube"
+	if selection.Quality != QualityYouTube || selection.Reason != expected {
+		t.Errorf("invalid fallback %s %s", selection.Quality, selection.Reason)
+	}
+}
+
+type HeadMock struct {
+	sizes map[string]int64
+}
+
+func (h *HeadMock) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != "HEAD" {
+		return nil, fmt.Errorf("invalid method %s", req.Method)
+	}
+
+	size, ok := h.sizes[req.URL.Path]
+	if !ok {
+		return nil, fmt.Errorf("unexpected URL %s", req.URL)
+	}
+
+	return &http.Response{
+		Body:          ioutil.NopCloser(strings.NewReader("")),
+		ContentLength: size,
+		StatusCode:    200,
+		Status:        "200",
+	}, nil
+}
+
+func TestParseQualityPolicy(t *testing.T) {
+	for _, s := range []string{"prefer-hd", "Prefer HD", "prefer_hd"} {
+		policy, err := ParseQualityPolicy(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy != PreferHD {
+			t.Errorf("parsed %q as %s expected %s", s, policy, PreferHD)
+		}
+	}
+
+	if _, err := ParseQualityPolicy("best"); err == nil {
+		t.Errorf("parsed unknown policy")
+	}
+}
+
+func TestProbeSizes(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &HeadMock{
+		sizes: map[string]int64{
+			"/video_low.mp4": 1000,
+			"/video_hd.mp4":  9000,
+		},
+	}
+
+	video := &VideoInfo{
+		LowURL:    "https://giantbomb.com/video_low.mp4",
+		HDURL:     "https://giantbomb.com/video_hd.mp4",
+		YoutubeID: "abc",
+	}
+	sizes, err := invoker.ProbeSizes(context.Background(), video)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sizes) != 2 || sizes[QualityLow] != 1000 || sizes[QualityHD] != 9000 {
+		t.Errorf("invalid sizes %v", sizes)
+	}
+
+	if _, err := invoker.ProbeSize(context.Background(), video, QualityYouTube); err == nil {
+		t.Errorf("probed youtube")
+	}
+}
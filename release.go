@@ -0,0 +1,207 @@
+package gbomb
+
+import (
+	"fmt"
+	"time"
+)
+
+//ReleasePrecision how precisely a release window is known
+type ReleasePrecision int
+
+//Release precisions from least to most precise
+const (
+	ReleaseTBA ReleasePrecision = iota
+	ReleaseYear
+	ReleaseQuarter
+	ReleaseMonth
+	ReleaseDay
+)
+
+//String returns the precision as a string
+func (p ReleasePrecision) String() string {
+	switch p {
+	case ReleaseYear:
+		return "year"
+	case ReleaseQuarter:
+		return "quarter"
+	case ReleaseMonth:
+		return "month"
+	case ReleaseDay:
+		return "day"
+	}
+
+	return "tba"
+}
+
+//ReleaseWindow when a game was or is expected to be released
+type ReleaseWindow struct {
+	Precision ReleasePrecision
+	Year      int
+	Quarter   int
+	Month     time.Month
+	Day       int
+	//Released the window comes from the original release date rather than
+	//the expected release fields
+	Released bool
+}
+
+//ReleaseWindow combines OriginalReleaseDate and the expected release fields
+//into a single window
+func (g *Game) ReleaseWindow() ReleaseWindow {
+	if tme, err := g.OriginalReleaseDate.GetTime(); err == nil {
+		return ReleaseWindow{
+			Precision: ReleaseDay,
+			Year:      tme.Year(),
+			Quarter:   quarterOf(tme.Month()),
+			Month:     tme.Month(),
+			Day:       tme.Day(),
+			Released:  true,
+		}
+	}
+
+	return NewReleaseWindow(
+		g.ExpectedReleaseYear, g.ExpectedReleaseQuarter,
+		g.ExpectedReleaseMonth, g.ExpectedReleaseDay,
+	)
+}
+
+//NewReleaseWindow creates a window from giant bomb's nullable expected release
+//fields using the most precise combination that is set
+func NewReleaseWindow(year, quarter, month, day *int) ReleaseWindow {
+	if year == nil || *year <= 0 {
+		return ReleaseWindow{Precision: ReleaseTBA}
+	}
+
+	w := ReleaseWindow{Precision: ReleaseYear, Year: *year}
+	if month != nil && *month >= 1 && *month <= 12 {
+		w.Month = time.Month(*month)
+		w.Quarter = quarterOf(w.Month)
+		w.Precision = ReleaseMonth
+
+		if day != nil && *day >= 1 && *day <= 31 {
+			w.Day = *day
+			w.Precision = ReleaseDay
+		}
+	} else if quarter != nil && *quarter >= 1 && *quarter <= 4 {
+		w.Quarter = *quarter
+		w.Precision = ReleaseQuarter
+	}
+
+	return w
+}
+
+func quarterOf(month time.Month) int {
+	return (int(month)-1)/3 + 1
+}
+
+//IsTBA returns if nothing is known about the release
+func (w ReleaseWindow) IsTBA() bool {
+	return w.Precision == ReleaseTBA
+}
+
+//Start returns the first day of the window in PacificTime or the zero time if TBA
+func (w ReleaseWindow) Start() time.Time {
+	switch w.Precision {
+	case ReleaseYear:
+		return time.Date(w.Year, time.January, 1, 0, 0, 0, 0, PacificTime)
+	case ReleaseQuarter:
+		return time.Date(w.Year, time.Month((w.Quarter-1)*3+1), 1, 0, 0, 0, 0, PacificTime)
+	case ReleaseMonth:
+		return time.Date(w.Year, w.Month, 1, 0, 0, 0, 0, PacificTime)
+	case ReleaseDay:
+		return time.Date(w.Year, w.Month, w.Day, 0, 0, 0, 0, PacificTime)
+	}
+
+	return time.Time{}
+}
+
+//End returns the first instant after the window or the zero time if TBA
+func (w ReleaseWindow) End() time.Time {
+	start := w.Start()
+	switch w.Precision {
+	case ReleaseYear:
+		return start.AddDate(1, 0, 0)
+	case ReleaseQuarter:
+		return start.AddDate(0, 3, 0)
+	case ReleaseMonth:
+		return start.AddDate(0, 1, 0)
+	case ReleaseDay:
+		return start.AddDate(0, 0, 1)
+	}
+
+	return time.Time{}
+}
+
+//Contains returns if t falls inside the window
+func (w ReleaseWindow) Contains(t time.Time) bool {
+	if w.IsTBA() {
+		return false
+	}
+
+	return !t.Before(w.Start()) && t.Before(w.End())
+}
+
+//Compare returns -1 if w sorts before other, 1 if after and 0 if equal
+//windows are ordered by when they end so vague windows such as "Q3 2027"
+//come after the dates inside them, TBA sorts last
+func (w ReleaseWindow) Compare(other ReleaseWindow) int {
+	if w.IsTBA() || other.IsTBA() {
+		switch {
+		case w.IsTBA() && other.IsTBA():
+			return 0
+		case w.IsTBA():
+			return 1
+		}
+		return -1
+	}
+
+	end, otherEnd := w.End(), other.End()
+	switch {
+	case end.Before(otherEnd):
+		return -1
+	case end.After(otherEnd):
+		return 1
+	case w.Precision > other.Precision:
+		return -1
+	case w.Precision < other.Precision:
+		return 1
+	}
+
+	return 0
+}
+
+//Before returns if w sorts before other
+func (w ReleaseWindow) Before(other ReleaseWindow) bool {
+	return w.Compare(other) < 0
+}
+
+//String returns a human readable window e.g. "Q3 2027"
+func (w ReleaseWindow) String() string {
+	switch w.Precision {
+	case ReleaseYear:
+		return fmt.Sprintf("%d", w.Year)
+	case ReleaseQuarter:
+		return fmt.Sprintf("Q%d %d", w.Quarter, w.Year)
+	case ReleaseMonth:
+		return fmt.Sprintf("%s %d", w.Month, w.Year)
+	case ReleaseDay:
+		return fmt.Sprintf("%s %d, %d", w.Month, w.Day, w.Year)
+	}
+
+	return "TBA"
+}
+
+//ByReleaseWindow sorts games by release window
+type ByReleaseWindow []Game
+
+func (g ByReleaseWindow) Len() int {
+	return len(g)
+}
+
+func (g ByReleaseWindow) Less(i, j int) bool {
+	return g[i].ReleaseWindow().Before(g[j].ReleaseWindow())
+}
+
+func (g ByReleaseWindow) Swap(i, j int) {
+	g[i], g[j] = g[j], g[i]
+}
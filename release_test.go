@@ -0,0 +1,81 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestReleaseWindow(t *testing.T) {
+	cases := []struct {
+		window   ReleaseWindow
+		expected string
+	}{
+		{NewReleaseWindow(nil, nil, nil, nil), "TBA"},
+		{NewReleaseWindow(intPtr(2027), nil, nil, nil), "2027"},
+		{NewReleaseWindow(intPtr(2027), intPtr(3), nil, nil), "Q3 2027"},
+		{NewReleaseWindow(intPtr(2027), intPtr(3), intPtr(8), nil), "August 2027"},
+		{NewReleaseWindow(intPtr(2027), nil, intPtr(8), intPtr(14)), "August 14, 2027"},
+	}
+	for _, c := range cases {
+		if c.window.String() != c.expected {
+			t.Errorf("invalid window %s expected %s", c.window, c.expected)
+		}
+	}
+
+	if !cases[3].window.Contains(cases[4].window.Start()) {
+		t.Errorf("%s does not contain %s", cases[3].window, cases[4].window)
+	}
+
+	if !cases[4].window.Before(cases[3].window) {
+		t.Errorf("%s should sort before %s", cases[4].window, cases[3].window)
+	}
+
+	if !cases[1].window.Before(cases[0].window) {
+		t.Errorf("TBA should sort last")
+	}
+}
+
+func TestGameReleaseWindow(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if game.ExpectedReleaseYear != nil {
+		t.Errorf("null expected release year decoded as %d", *game.ExpectedReleaseYear)
+	}
+
+	window := game.ReleaseWindow()
+	if !window.Released || window.String() != "October 27, 2017" {
+		t.Errorf("invalid release window %s", window)
+	}
+
+	var upcoming []Game
+	err = json.Unmarshal([]byte(`[
+		{"name": "TBA", "expected_release_year": null},
+		{"name": "Q3", "expected_release_year": 2027, "expected_release_quarter": 3},
+		{"name": "July", "expected_release_year": 2027, "expected_release_month": 7},
+		{"name": "2026", "expected_release_year": 2026}
+	]`), &upcoming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upcoming = append(upcoming, *game)
+
+	sort.Sort(ByReleaseWindow(upcoming))
+
+	expected := []string{"Super Mario Odyssey", "2026", "July", "Q3", "TBA"}
+	for i, name := range expected {
+		if upcoming[i].Name != name {
+			t.Errorf("invalid order at %d was %s expected %s", i, upcoming[i].Name, name)
+		}
+	}
+}
@@ -0,0 +1,610 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//XML namespaces used by podcast feeds
+const (
+	ITunesNamespace = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+	MediaNamespace  = "http://search.yahoo.com/mrss/"
+	AtomNamespace   = "http://www.w3.org/2005/Atom"
+)
+
+//ITunesImage an itunes:image artwork link
+type ITunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+//ITunesCategory an itunes:category which may hold subcategories
+type ITunesCategory struct {
+	Text          string           `xml:"text,attr"`
+	Subcategories []ITunesCategory `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd category"`
+}
+
+//ITunesOwner the itunes:owner contact of a channel
+type ITunesOwner struct {
+	Name  string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd name"`
+	Email string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd email"`
+}
+
+//ITunesExplicit an itunes:explicit flag, yes, true and explicit are explicit
+type ITunesExplicit bool
+
+//UnmarshalText decodes the flag
+func (e *ITunesExplicit) UnmarshalText(text []byte) error {
+	switch strings.ToLower(strings.TrimSpace(string(text))) {
+	case "yes", "true", "explicit":
+		*e = true
+	default:
+		*e = false
+	}
+
+	return nil
+}
+
+//MarshalText encodes the flag as yes or no
+func (e ITunesExplicit) MarshalText() ([]byte, error) {
+	if e {
+		return []byte("yes"), nil
+	}
+
+	return []byte("no"), nil
+}
+
+//RSSImage a channel's RSS image
+type RSSImage struct {
+	URL    string `xml:"url"`
+	Title  string `xml:"title"`
+	Link   string `xml:"link"`
+	Width  int    `xml:"width"`
+	Height int    `xml:"height"`
+}
+
+//AtomLink an atom:link to a feed
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+//RSSEnclosure the media file attached to an entry
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+//MediaContent a media:content file
+type MediaContent struct {
+	URL      string `xml:"url,attr"`
+	FileSize int64  `xml:"fileSize,attr"`
+	Type     string `xml:"type,attr"`
+}
+
+//RSSFeedEntry a giant bomb RSS feed entry
+type RSSFeedEntry struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	PubDateStr  string `xml:"pubDate"`
+	GUID        string `xml:"guid"`
+	//itunes elements are matched before the plain ones sharing their names
+	Subtitle    string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd subtitle"`
+	Summary     string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	Author      string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+	Explicit    ITunesExplicit `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit"`
+	DurationStr string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	Keywords    string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd keywords"`
+	Image       ITunesImage    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	Content     []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	Enclosure   RSSEnclosure   `xml:"enclosure"`
+	//PublishTime PubDateStr parsed when the entry is decoded, zero if it
+	//could not be parsed
+	PublishTime time.Time `xml:"-"`
+}
+
+//UnmarshalXML custom xml unmarshaler populating PublishTime
+func (r *RSSFeedEntry) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type entryAlias RSSFeedEntry
+	var tmp entryAlias
+	err := d.DecodeElement(&tmp, &start)
+	if err != nil {
+		return err
+	}
+
+	*r = RSSFeedEntry(tmp)
+	r.PublishTime, _ = ParsePubDate(r.PubDateStr)
+
+	return nil
+}
+
+//GetPublishTime returns the publish time as a time object
+func (r *RSSFeedEntry) GetPublishTime() (time.Time, error) {
+	return ParsePubDate(r.PubDateStr)
+}
+
+//pubDateLayouts RFC 822 and 1123 variants with the weekday removed
+var pubDateLayouts = []string{
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04 MST",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04:05 MST",
+	"2 Jan 06 15:04 -0700",
+	"2 Jan 06 15:04 MST",
+}
+
+func fixedZone(name string, hours int) *time.Location {
+	return time.FixedZone(name, hours*60*60)
+}
+
+//pubDateZones named zones allowed by RFC 822 and common US abbreviations,
+//time.Parse gives unknown abbreviations a zero offset
+var pubDateZones = map[string]*time.Location{
+	"UT":   time.UTC,
+	"UTC":  time.UTC,
+	"GMT":  time.UTC,
+	"Z":    time.UTC,
+	"EST":  fixedZone("EST", -5),
+	"EDT":  fixedZone("EDT", -4),
+	"CST":  fixedZone("CST", -6),
+	"CDT":  fixedZone("CDT", -5),
+	"MST":  fixedZone("MST", -7),
+	"MDT":  fixedZone("MDT", -6),
+	"PST":  fixedZone("PST", -8),
+	"PDT":  fixedZone("PDT", -7),
+	"AKST": fixedZone("AKST", -9),
+	"AKDT": fixedZone("AKDT", -8),
+	"HST":  fixedZone("HST", -10),
+}
+
+//ParsePubDate parses an RSS pubDate in RFC 822 or RFC 1123 form with either a
+//numeric offset or a named zone, the weekday and seconds are optional
+func ParsePubDate(s string) (time.Time, error) {
+	value := strings.Join(strings.Fields(s), " ")
+	if comma := strings.Index(value, ","); comma >= 0 && comma <= 9 {
+		value = strings.TrimSpace(value[comma+1:])
+	}
+
+	var zone *time.Location
+	if space := strings.LastIndex(value, " "); space >= 0 {
+		name := strings.ToUpper(value[space+1:])
+		if loc, ok := pubDateZones[name]; ok {
+			zone = loc
+			//parse in UTC then move the wall clock into the zone
+			value = value[:space] + " +0000"
+		}
+	}
+
+	for _, layout := range pubDateLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+
+		if zone != nil {
+			return time.Date(
+				t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, zone,
+			), nil
+		}
+		if name, offset := t.Zone(); offset == 0 && name != "" && name != "UTC" {
+			return time.Time{}, fmt.Errorf("unknown timezone %q in pubDate %q", name, s)
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid pubDate %q", s)
+}
+
+//GetDuration returns the itunes:duration which may be seconds, MM:SS or
+//HH:MM:SS, an empty duration is 0
+func (r *RSSFeedEntry) GetDuration() (time.Duration, error) {
+	return parseITunesDuration(r.DurationStr)
+}
+
+func parseITunesDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var seconds int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		seconds = seconds*60 + n
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+//File returns the entry's media file from its enclosure falling back to the
+//first media:content
+func (r *RSSFeedEntry) File() (RSSEnclosure, error) {
+	if r.Enclosure.URL != "" {
+		return r.Enclosure, nil
+	}
+
+	for _, content := range r.Content {
+		if content.URL != "" {
+			return RSSEnclosure{URL: content.URL, Length: content.FileSize, Type: content.Type}, nil
+		}
+	}
+
+	return RSSEnclosure{}, fmt.Errorf("entry %q has no enclosure or media:content", r.GUID)
+}
+
+//SynthesizedLink builds the legacy podtrac audio.mp3 link from the entry's
+//GUID, it may not match the real file and is only used by DownloadLink when
+//Invoker.SynthesizePodcastLinks is set
+func (r *RSSFeedEntry) SynthesizedLink() (string, error) {
+	guid := GUID(strings.TrimSpace(r.GUID))
+	if err := guid.Validate(); err != nil {
+		return "", fmt.Errorf("cannot build a download link for entry %q: %v", r.GUID, err)
+	}
+
+	return fmt.Sprintf(
+		"https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/%d/audio.mp3",
+		guid.ID(),
+	), nil
+}
+
+//DownloadLink returns the URL an entry is downloaded from
+func (i *Invoker) DownloadLink(r *RSSFeedEntry) (string, error) {
+	file, err := r.File()
+	if err == nil {
+		return file.URL, nil
+	}
+	if !i.SynthesizePodcastLinks {
+		return "", err
+	}
+
+	return r.SynthesizedLink()
+}
+
+//Download returns a IO read Write closer of the download stream for an rss feed entry,
+//like feeds the API key is only sent to the API endpoint's host
+func (r *RSSFeedEntry) Download(i *Invoker) (io.ReadCloser, error) {
+	link, err := i.DownloadLink(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := i.newPodcastRequest(context.Background(), "GET", link)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.isAPIHost(req.URL) {
+		err = i.requestLimiter(req.Context())
+		if err != nil {
+			return nil, err
+		}
+	}
+	res, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+//RSSChannel a Giant bomb RSS channel
+type RSSChannel struct {
+	Title string `xml:"title"`
+	//namespaced elements are matched before the plain ones sharing their
+	//names so atom:link and itunes:image do not overwrite link and image
+	AtomLink    AtomLink         `xml:"http://www.w3.org/2005/Atom link"`
+	Link        string           `xml:"link"`
+	Description string           `xml:"description"`
+	Language    string           `xml:"language"`
+	Copyright   string           `xml:"copyright"`
+	ITunesImage ITunesImage      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	Image       RSSImage         `xml:"image"`
+	Subtitle    string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd subtitle"`
+	Summary     string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	Author      string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+	Owner       ITunesOwner      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd owner"`
+	Categories  []ITunesCategory `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd category"`
+	Explicit    ITunesExplicit   `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit"`
+	Keywords    string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd keywords"`
+	Entries     []RSSFeedEntry   `xml:"item"`
+}
+
+//KeywordList splits a comma separated keywords element
+func KeywordList(keywords string) []string {
+	var list []string
+	for _, keyword := range strings.Split(keywords, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			list = append(list, keyword)
+		}
+	}
+
+	return list
+}
+
+type rssBase struct {
+	Channel RSSChannel `xml:"channel"`
+}
+
+//GetPodcasts returns the RSSChannel Feed for a feed id from the invoker's
+//catalogue, ids not in the catalogue are fetched from /podcast-xml/{feed}/
+func (i *Invoker) GetPodcasts(feed string) (*RSSChannel, error) {
+	podcast, _ := i.PodcastFeed(feed)
+
+	return i.GetPodcastFeed(podcast)
+}
+
+//GetPodcastFeed returns the RSSChannel for a feed, the API key is only sent
+//to the API endpoint's host
+func (i *Invoker) GetPodcastFeed(feed PodcastFeed) (*RSSChannel, error) {
+	req, err := i.newFeedRequest(context.Background(), feed)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.requestLimiter(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	res, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("requesting %s returned %s", feed.ID, res.Status)
+	}
+
+	return decodeFeed(res.Body)
+}
+
+//newPodcastRequest creates a request only carrying the API key to the API host
+func (i *Invoker) newPodcastRequest(ctx context.Context, method, link string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	if i.isAPIHost(req.URL) {
+		q := req.URL.Query()
+		q.Add("api_key", i.APIKey)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	return req, nil
+}
+
+func (i *Invoker) newFeedRequest(ctx context.Context, feed PodcastFeed) (*http.Request, error) {
+	return i.newPodcastRequest(ctx, "GET", feed.URL(i.Endpoint))
+}
+
+func decodeFeed(r io.Reader) (*RSSChannel, error) {
+	bodyXML, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rss rssBase
+	err = xml.Unmarshal([]byte(bodyXML), &rss)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rss.Channel, nil
+}
+
+//the feed is written with literal prefixed names as encoding/xml would
+//otherwise declare each namespace as a default on every element which some
+//podcast clients do not understand
+type rssOutput struct {
+	XMLName xml.Name      `xml:"rss"`
+	Version string        `xml:"version,attr"`
+	ITunes  string        `xml:"xmlns:itunes,attr"`
+	Media   string        `xml:"xmlns:media,attr"`
+	Atom    string        `xml:"xmlns:atom,attr"`
+	Channel channelOutput `xml:"channel"`
+}
+
+type atomLinkOutput struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type itunesImageOutput struct {
+	Href string `xml:"href,attr"`
+}
+
+type itunesCategoryOutput struct {
+	Text          string                 `xml:"text,attr"`
+	Subcategories []itunesCategoryOutput `xml:"itunes:category"`
+}
+
+type itunesOwnerOutput struct {
+	Name  string `xml:"itunes:name,omitempty"`
+	Email string `xml:"itunes:email,omitempty"`
+}
+
+type rssImageOutput struct {
+	URL    string `xml:"url"`
+	Title  string `xml:"title"`
+	Link   string `xml:"link"`
+	Width  int    `xml:"width,omitempty"`
+	Height int    `xml:"height,omitempty"`
+}
+
+type mediaContentOutput struct {
+	URL      string `xml:"url,attr"`
+	FileSize int64  `xml:"fileSize,attr,omitempty"`
+	Type     string `xml:"type,attr,omitempty"`
+}
+
+type enclosureOutput struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type guidOutput struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type channelOutput struct {
+	Title       string                 `xml:"title"`
+	Link        string                 `xml:"link"`
+	Description string                 `xml:"description"`
+	Language    string                 `xml:"language,omitempty"`
+	Copyright   string                 `xml:"copyright,omitempty"`
+	AtomLink    *atomLinkOutput        `xml:"atom:link,omitempty"`
+	Image       *rssImageOutput        `xml:"image,omitempty"`
+	ITunesImage *itunesImageOutput     `xml:"itunes:image,omitempty"`
+	Subtitle    string                 `xml:"itunes:subtitle,omitempty"`
+	Summary     string                 `xml:"itunes:summary,omitempty"`
+	Author      string                 `xml:"itunes:author,omitempty"`
+	Owner       *itunesOwnerOutput     `xml:"itunes:owner,omitempty"`
+	Categories  []itunesCategoryOutput `xml:"itunes:category"`
+	Explicit    ITunesExplicit         `xml:"itunes:explicit"`
+	Keywords    string                 `xml:"itunes:keywords,omitempty"`
+	Items       []itemOutput           `xml:"item"`
+}
+
+type itemOutput struct {
+	Title       string               `xml:"title"`
+	Link        string               `xml:"link,omitempty"`
+	Description string               `xml:"description,omitempty"`
+	PubDate     string               `xml:"pubDate,omitempty"`
+	GUID        *guidOutput          `xml:"guid,omitempty"`
+	Subtitle    string               `xml:"itunes:subtitle,omitempty"`
+	Summary     string               `xml:"itunes:summary,omitempty"`
+	Author      string               `xml:"itunes:author,omitempty"`
+	Explicit    ITunesExplicit       `xml:"itunes:explicit"`
+	Duration    string               `xml:"itunes:duration,omitempty"`
+	Keywords    string               `xml:"itunes:keywords,omitempty"`
+	Image       *itunesImageOutput   `xml:"itunes:image,omitempty"`
+	Content     []mediaContentOutput `xml:"media:content"`
+	Enclosure   *enclosureOutput     `xml:"enclosure,omitempty"`
+}
+
+func categoryOutputs(categories []ITunesCategory) []itunesCategoryOutput {
+	var result []itunesCategoryOutput
+	for _, category := range categories {
+		result = append(result, itunesCategoryOutput{
+			Text:          category.Text,
+			Subcategories: categoryOutputs(category.Subcategories),
+		})
+	}
+
+	return result
+}
+
+func itemOutputFrom(entry *RSSFeedEntry) itemOutput {
+	item := itemOutput{
+		Title:       entry.Title,
+		Link:        entry.Link,
+		Description: entry.Description,
+		PubDate:     entry.PubDateStr,
+		Subtitle:    entry.Subtitle,
+		Summary:     entry.Summary,
+		Author:      entry.Author,
+		Explicit:    entry.Explicit,
+		Duration:    entry.DurationStr,
+		Keywords:    entry.Keywords,
+	}
+	if !entry.PublishTime.IsZero() {
+		item.PubDate = entry.PublishTime.Format(time.RFC1123Z)
+	}
+	if entry.GUID != "" {
+		item.GUID = &guidOutput{
+			IsPermaLink: strings.HasPrefix(entry.GUID, "http"),
+			Value:       entry.GUID,
+		}
+	}
+	if entry.Image.Href != "" {
+		item.Image = &itunesImageOutput{Href: entry.Image.Href}
+	}
+	for _, content := range entry.Content {
+		item.Content = append(item.Content, mediaContentOutput(content))
+	}
+	if entry.Enclosure.URL != "" {
+		enclosure := enclosureOutput(entry.Enclosure)
+		item.Enclosure = &enclosure
+	}
+
+	return item
+}
+
+//WriteRSS writes the channel as an RSS 2.0 feed with iTunes and media
+//elements
+func (c *RSSChannel) WriteRSS(w io.Writer) error {
+	channel := channelOutput{
+		Title:       c.Title,
+		Link:        c.Link,
+		Description: c.Description,
+		Language:    c.Language,
+		Copyright:   c.Copyright,
+		Subtitle:    c.Subtitle,
+		Summary:     c.Summary,
+		Author:      c.Author,
+		Categories:  categoryOutputs(c.Categories),
+		Explicit:    c.Explicit,
+		Keywords:    c.Keywords,
+	}
+	if c.AtomLink.Href != "" {
+		link := atomLinkOutput(c.AtomLink)
+		channel.AtomLink = &link
+	}
+	if c.Image.URL != "" {
+		image := rssImageOutput(c.Image)
+		channel.Image = &image
+	}
+	if c.ITunesImage.Href != "" {
+		channel.ITunesImage = &itunesImageOutput{Href: c.ITunesImage.Href}
+	}
+	if c.Owner != (ITunesOwner{}) {
+		channel.Owner = &itunesOwnerOutput{Name: c.Owner.Name, Email: c.Owner.Email}
+	}
+	for idx := range c.Entries {
+		channel.Items = append(channel.Items, itemOutputFrom(&c.Entries[idx]))
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(rssOutput{
+		Version: "2.0",
+		ITunes:  ITunesNamespace,
+		Media:   MediaNamespace,
+		Atom:    AtomNamespace,
+		Channel: channel,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
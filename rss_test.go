@@ -0,0 +1,209 @@
+package gbomb
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestRSSChannelMetadata(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &PodcastFeedMock{}
+	feed, err := invoker.GetPodcasts("bombcast")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.Link != "https://www.giantbomb.com" {
+		t.Errorf("invalid link %q", feed.Link)
+	}
+	if feed.AtomLink.Rel != "self" {
+		t.Errorf("invalid atom link %+v", feed.AtomLink)
+	}
+	if feed.Copyright != "2008-2021 CBS Interactive" {
+		t.Errorf("invalid copyright %q", feed.Copyright)
+	}
+	if feed.Image.URL != "https://giantbomb1.cbsistatic.com/uploads/original/11/110673/2927815-3756859778-28940.png" || feed.Image.Width != 144 {
+		t.Errorf("invalid image %+v", feed.Image)
+	}
+	if feed.ITunesImage.Href != "https://giantbomb1.cbsistatic.com/uploads/original/11/110673/2927815-3756859778-28940.png?20210209" {
+		t.Errorf("invalid itunes image %q", feed.ITunesImage.Href)
+	}
+	if feed.Owner != (ITunesOwner{Name: "Giant Bomb", Email: "bombcast@giantbomb.com"}) {
+		t.Errorf("invalid owner %+v", feed.Owner)
+	}
+	if len(feed.Categories) != 3 || feed.Categories[0].Text != "Games & Hobbies" ||
+		len(feed.Categories[0].Subcategories) != 1 || feed.Categories[0].Subcategories[0].Text != "Video Games" {
+		t.Errorf("invalid categories %+v", feed.Categories)
+	}
+	if keywords := KeywordList(feed.Keywords); len(keywords) != 11 || keywords[2] != "Jeff Gerstmann" {
+		t.Errorf("invalid keywords %v", keywords)
+	}
+	if feed.Explicit {
+		t.Errorf("feed is not explicit")
+	}
+
+	entry := feed.Entries[0]
+	if entry.Link != "https://www.giantbomb.com/shows/672-great-conversations/2970-20948/free-podcast" {
+		t.Errorf("invalid entry link %q", entry.Link)
+	}
+	if entry.Summary == "" || entry.Summary != entry.Description {
+		t.Errorf("invalid summary %q", entry.Summary)
+	}
+	if entry.Image.Href != "https://giantbomb1.cbsistatic.com/uploads/original/0/30/3272163-041iaezvk6721.png?20210209" {
+		t.Errorf("invalid entry image %q", entry.Image.Href)
+	}
+
+	enclosure := "https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/3246/vf_bc_672_020921_62-02-09-2021-2391958662.mp3"
+	if entry.Enclosure != (RSSEnclosure{URL: enclosure, Length: 1, Type: "audio/mpeg"}) {
+		t.Errorf("invalid enclosure %+v", entry.Enclosure)
+	}
+	if len(entry.Content) != 1 || entry.Content[0].URL != enclosure {
+		t.Errorf("invalid media content %+v", entry.Content)
+	}
+
+	last := feed.Entries[len(feed.Entries)-1]
+	duration, err := last.GetDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if duration != 5283*time.Second {
+		t.Errorf("invalid duration %s", duration)
+	}
+	if last.Content[0].FileSize != 63404564 {
+		t.Errorf("invalid file size %d", last.Content[0].FileSize)
+	}
+}
+
+func TestParseITunesDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":         0,
+		"4355":     4355 * time.Second,
+		"12:05":    12*time.Minute + 5*time.Second,
+		"01:12:05": time.Hour + 12*time.Minute + 5*time.Second,
+	}
+	for s, expected := range cases {
+		duration, err := parseITunesDuration(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if duration != expected {
+			t.Errorf("parsed %q as %s expected %s", s, duration, expected)
+		}
+	}
+
+	if _, err := parseITunesDuration("an hour"); err == nil {
+		t.Errorf("parsed invalid duration")
+	}
+}
+
+func TestDownloadLink(t *testing.T) {
+	invoker := createTestInvoker()
+
+	media := &RSSFeedEntry{
+		GUID: "1600-21",
+		Content: []MediaContent{
+			{URL: "https://www.giantbomb.com/podcasts/download/21/bombcast.mp3", FileSize: 100, Type: "audio/mpeg"},
+		},
+	}
+	file, err := media.File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file != (RSSEnclosure{URL: "https://www.giantbomb.com/podcasts/download/21/bombcast.mp3", Length: 100, Type: "audio/mpeg"}) {
+		t.Errorf("invalid media content file %+v", file)
+	}
+
+	bare := &RSSFeedEntry{GUID: "1600-21"}
+	if _, err := invoker.DownloadLink(bare); err == nil {
+		t.Errorf("synthesized a link without SynthesizePodcastLinks set")
+	}
+
+	invoker.SynthesizePodcastLinks = true
+	link, err := invoker.DownloadLink(bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link != "https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/21/audio.mp3" {
+		t.Errorf("invalid synthesized link %s", link)
+	}
+
+	malformed := &RSSFeedEntry{GUID: "bombcast"}
+	if _, err := invoker.DownloadLink(malformed); err == nil {
+		t.Errorf("synthesized a link from a malformed GUID")
+	}
+}
+
+func TestParsePubDate(t *testing.T) {
+	expected := time.Date(2021, time.February, 9, 22, 52, 0, 0, time.UTC)
+	cases := []string{
+		"Tue, 09 Feb 2021 14:52:00 PST",
+		"Tue, 9 Feb 2021 15:52:00 MST",
+		"Tue, 09 Feb 2021 17:52:00 EST",
+		"Tue, 09 Feb 2021 17:52:00 -0500",
+		"09 Feb 2021 22:52:00 GMT",
+		"Tue, 09 Feb 21 22:52 +0000",
+		"Tue,  09 Feb 2021 15:52:00 PDT",
+		"Tue, 09 Feb 2021 22:52:00 UT",
+	}
+	for _, s := range cases {
+		tme, err := ParsePubDate(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !tme.Equal(expected) {
+			t.Errorf("parsed %q as %s expected %s", s, tme, expected)
+		}
+	}
+
+	for _, s := range []string{"", "yesterday", "Tue, 09 Feb 2021 14:52:00 XYZ"} {
+		if _, err := ParsePubDate(s); err == nil {
+			t.Errorf("parsed invalid pubDate %q", s)
+		}
+	}
+}
+
+func TestPublishTimeDecoded(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &PodcastFeedMock{}
+	feed, err := invoker.GetPodcasts("bombcast")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range feed.Entries {
+		if entry.PublishTime.IsZero() {
+			t.Fatalf("entry %s has no publish time from %q", entry.GUID, entry.PubDateStr)
+		}
+	}
+
+	first := feed.Entries[0].PublishTime
+	if _, offset := first.Zone(); offset != -8*60*60 {
+		t.Errorf("PST parsed with offset %d", offset)
+	}
+}
+
+func TestRSSFeedEntryDownload(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &RouteMock{routes: map[string]string{
+		"https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/3246/audio.mp3": "test_data/chats.json",
+		"https://www.giantbomb.com/podcasts/download/3249/bta_021121.mp3?api_key=coolbeans":       "test_data/promos.json",
+	}}
+
+	for _, link := range []string{
+		"https://dts.podtrac.com/redirect.mp3/www.giantbomb.com/podcasts/download/3246/audio.mp3",
+		"https://www.giantbomb.com/podcasts/download/3249/bta_021121.mp3",
+	} {
+		entry := &RSSFeedEntry{Enclosure: RSSEnclosure{URL: link}}
+		body, err := entry.Download(invoker)
+		if err != nil {
+			t.Errorf("downloading %s: %v", link, err)
+			continue
+		}
+		data, err := ioutil.ReadAll(body)
+		body.Close()
+		if err != nil || len(data) == 0 {
+			t.Errorf("empty download from %s", link)
+		}
+	}
+}
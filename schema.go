@@ -0,0 +1,175 @@
+package gbomb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//SchemaIssue a key in an API payload with no matching struct field
+type SchemaIssue struct {
+	//Path to the key e.g. results.images[0].original
+	Path string
+	//Type the Go type the key could not be mapped onto
+	Type string
+}
+
+//String returns the issue as a string
+func (s SchemaIssue) String() string {
+	return fmt.Sprintf("unmapped field %s on %s", s.Path, s.Type)
+}
+
+//SchemaError returned in strict decoding mode when a payload has unmapped fields
+type SchemaError struct {
+	Issues []SchemaIssue
+}
+
+func (s *SchemaError) Error() string {
+	issues := make([]string, len(s.Issues))
+	for i, issue := range s.Issues {
+		issues[i] = issue.String()
+	}
+
+	return fmt.Sprintf("schema drift: %s", strings.Join(issues, ", "))
+}
+
+//knownFieldser lets types with a custom UnmarshalJSON list the keys they map
+type knownFieldser interface {
+	knownFields() []string
+}
+
+var (
+	knownFieldserType = reflect.TypeOf((*knownFieldser)(nil)).Elem()
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+)
+
+//CheckSchema reports every key in data that would be silently dropped when
+//decoding into v, like json.Decoder.DisallowUnknownFields but listing all of
+//them instead of failing on the first
+func CheckSchema(data []byte, v interface{}) ([]SchemaIssue, error) {
+	var payload interface{}
+	err := json.Unmarshal(data, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []SchemaIssue
+	checkSchemaValue(payload, reflect.TypeOf(v), "", &issues)
+
+	return issues, nil
+}
+
+func checkSchemaValue(payload interface{}, t reflect.Type, path string, issues *[]SchemaIssue) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t == rawMessageType || t.Kind() == reflect.Interface {
+		return
+	}
+
+	switch p := payload.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			checkSchemaObject(p, t, path, issues)
+		case reflect.Map:
+			for key, value := range p {
+				checkSchemaValue(value, t.Elem(), joinSchemaPath(path, key), issues)
+			}
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for i, value := range p {
+			checkSchemaValue(value, t.Elem(), fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+	}
+}
+
+func checkSchemaObject(payload map[string]interface{}, t reflect.Type, path string, issues *[]SchemaIssue) {
+	if reflect.PtrTo(t).Implements(knownFieldserType) {
+		known := make(map[string]bool)
+		for _, name := range reflect.New(t).Interface().(knownFieldser).knownFields() {
+			known[name] = true
+		}
+		for _, key := range sortedKeys(payload) {
+			if !known[key] {
+				*issues = append(*issues, SchemaIssue{Path: joinSchemaPath(path, key), Type: t.String()})
+			}
+		}
+		return
+	}
+
+	fields := make(map[string]reflect.Type)
+	collectSchemaFields(t, fields)
+
+	for _, key := range sortedKeys(payload) {
+		fieldType, ok := fields[key]
+		if !ok {
+			fieldType, ok = fields[strings.ToLower(key)]
+		}
+		if !ok {
+			*issues = append(*issues, SchemaIssue{Path: joinSchemaPath(path, key), Type: t.String()})
+			continue
+		}
+
+		checkSchemaValue(payload[key], fieldType, joinSchemaPath(path, key), issues)
+	}
+}
+
+//collectSchemaFields maps json keys and their lower case form to field types
+//following encoding/json's rules for tags and embedded structs
+func collectSchemaFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectSchemaFields(embedded, fields)
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[name] = field.Type
+		if _, ok := fields[strings.ToLower(name)]; !ok {
+			fields[strings.ToLower(name)] = field.Type
+		}
+	}
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,113 @@
+package gbomb
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//fixtureTypes maps every JSON fixture in test_data to the type it decodes into
+var fixtureTypes = map[string]interface{}{
+	"gameRequest.json": &gameResponseInternal{},
+	"gameSearch.json":  &GamesResponse{},
+	"promos.json":      &PromosResponse{},
+	"chats.json":       &ChatsResponse{},
+	"types.json":       &TypesResponse{},
+	"franchise.json":   &resourceResponseInternal{},
+	"video.json":       &videoResponseInternal{},
+}
+
+func TestFixtureSchemas(t *testing.T) {
+	files, err := filepath.Glob("test_data/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		target, ok := fixtureTypes[filepath.Base(file)]
+		if !ok {
+			t.Errorf("fixture %s has no type registered in fixtureTypes", file)
+			continue
+		}
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err := CheckSchema(data, target)
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+
+		for _, issue := range issues {
+			t.Errorf("%s: %s", file, issue)
+		}
+	}
+}
+
+type BytesMock struct {
+	body string
+}
+
+func (b *BytesMock) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Body:       ioutil.NopCloser(strings.NewReader(b.body)),
+		StatusCode: 200,
+		Status:     "200",
+	}, nil
+}
+
+func TestStrictDecoding(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.StrictDecoding = true
+	invoker.client = &BytesMock{
+		body: `{"error": "OK", "results": {"name": "Bangai-O", "deck": "Robots", "boss_fights": 12}}`,
+	}
+
+	_, err := invoker.GetGame(context.Background(), "3030-56733")
+	schemaErr, ok := err.(*SchemaError)
+	if !ok {
+		t.Fatalf("returned %v expected a *SchemaError", err)
+	}
+
+	if len(schemaErr.Issues) != 1 || schemaErr.Issues[0].Path != "results.boss_fights" {
+		t.Errorf("invalid issues %v", schemaErr.Issues)
+	}
+
+	invoker.StrictDecoding = false
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if game.Deck != "Robots" {
+		t.Errorf("invalid deck %s expected %s", game.Deck, "Robots")
+	}
+}
+
+func TestImageAlternateKeys(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &GameMock{}
+	game, err := invoker.GetGame(context.Background(), "3030-56733")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "https://giantbomb1.cbsistatic.com/uploads/original/29/290345/3006500-56733-2.jpg"
+	if game.Images[0].OriginalURL != expected {
+		t.Errorf("invalid original URL %s expected %s", game.Images[0].OriginalURL, expected)
+	}
+
+	if game.Images[0].ImageTags != "All Images, Screenshots" {
+		t.Errorf("invalid image tags %s expected %s", game.Images[0].ImageTags, "All Images, Screenshots")
+	}
+
+	if game.Image.OriginalURL == "" {
+		t.Errorf("original_url was not decoded")
+	}
+}
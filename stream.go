@@ -0,0 +1,128 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+//FeedDecoder streams entries from an RSS feed without holding the whole
+//document in memory
+type FeedDecoder struct {
+	//Title the channel title, set once the decoder has read past it
+	Title string
+	//Limit stops the stream after this many entries when above 0
+	Limit int
+	//StopAtGUID stops the stream before the entry with this GUID e.g. the
+	//newest entry already processed
+	StopAtGUID string
+
+	decoder *xml.Decoder
+	body    io.Closer
+	depth   int
+	count   int
+	done    bool
+}
+
+//NewFeedDecoder Creates a decoder reading a feed from r
+func NewFeedDecoder(r io.Reader) *FeedDecoder {
+	return &FeedDecoder{decoder: xml.NewDecoder(r)}
+}
+
+//OpenPodcastFeed requests a feed returning a decoder streaming its entries,
+//the decoder must be closed
+func (i *Invoker) OpenPodcastFeed(ctx context.Context, feed PodcastFeed) (*FeedDecoder, error) {
+	req, err := i.newFeedRequest(ctx, feed)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.requestLimiter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("requesting %s returned %s", feed.ID, res.Status)
+	}
+
+	d := NewFeedDecoder(res.Body)
+	d.body = res.Body
+
+	return d, nil
+}
+
+//Next returns the next entry, io.EOF is returned at the end of the feed or
+//once a cutoff is reached
+func (d *FeedDecoder) Next() (*RSSFeedEntry, error) {
+	if d.done || (d.Limit > 0 && d.count >= d.Limit) {
+		return nil, io.EOF
+	}
+
+	for {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			//rss is depth 1, channel 2 and its children 3
+			if d.depth == 2 && t.Name.Local == "item" {
+				var entry RSSFeedEntry
+				err := d.decoder.DecodeElement(&entry, &t)
+				if err != nil {
+					return nil, err
+				}
+
+				if d.StopAtGUID != "" && entry.GUID == d.StopAtGUID {
+					d.done = true
+					return nil, io.EOF
+				}
+				d.count++
+
+				return &entry, nil
+			}
+			if d.depth == 2 && t.Name.Local == "title" && t.Name.Space == "" {
+				err := d.decoder.DecodeElement(&d.Title, &t)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			d.depth++
+		case xml.EndElement:
+			d.depth--
+		}
+	}
+}
+
+//Entries reads the remaining entries up to any cutoff
+func (d *FeedDecoder) Entries() ([]RSSFeedEntry, error) {
+	var entries []RSSFeedEntry
+	for {
+		entry, err := d.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, *entry)
+	}
+}
+
+//Close closes the response body of a decoder from OpenPodcastFeed
+func (d *FeedDecoder) Close() error {
+	if d.body == nil {
+		return nil
+	}
+
+	return d.body.Close()
+}
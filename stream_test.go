@@ -0,0 +1,124 @@
+package gbomb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestFeedDecoder(t *testing.T) {
+	data, err := ioutil.ReadFile("test_data/bombcast_feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel, err := decodeFeed(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := NewFeedDecoder(bytes.NewReader(data))
+	entries, err := decoder.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoder.Title != "Giant Bombcast" {
+		t.Errorf("invalid title %q", decoder.Title)
+	}
+	if !reflect.DeepEqual(entries, channel.Entries) {
+		t.Errorf("streamed entries differ from unmarshalled entries")
+	}
+
+	limited := NewFeedDecoder(bytes.NewReader(data))
+	limited.Limit = 5
+	entries, err = limited.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 {
+		t.Errorf("limited stream returned %d entries expected %d", len(entries), 5)
+	}
+
+	cutoff := NewFeedDecoder(bytes.NewReader(data))
+	cutoff.StopAtGUID = channel.Entries[3].GUID
+	entries, err = cutoff.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[2].GUID != channel.Entries[2].GUID {
+		t.Errorf("stream stopped at the wrong entry %d entries", len(entries))
+	}
+	if _, err := cutoff.Next(); err != io.EOF {
+		t.Errorf("stream continued after cutoff %v", err)
+	}
+}
+
+func TestOpenPodcastFeed(t *testing.T) {
+	invoker := createTestInvoker()
+	invoker.client = &PodcastFeedMock{}
+
+	decoder, err := invoker.OpenPodcastFeed(context.Background(), FeedBombcast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer decoder.Close()
+
+	entry, err := decoder.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.GUID != "1600-3246" {
+		t.Errorf("invalid first entry %s", entry.GUID)
+	}
+}
+
+func benchmarkFeed(b *testing.B) []byte {
+	data, err := ioutil.ReadFile("test_data/bombcast_feed.xml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	return data
+}
+
+func BenchmarkDecodeFeed(b *testing.B) {
+	data := benchmarkFeed(b)
+	for n := 0; n < b.N; n++ {
+		if _, err := decodeFeed(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFeedDecoder(b *testing.B) {
+	data := benchmarkFeed(b)
+	for n := 0; n < b.N; n++ {
+		decoder := NewFeedDecoder(bytes.NewReader(data))
+		for {
+			_, err := decoder.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkFeedDecoderLimit(b *testing.B) {
+	data := benchmarkFeed(b)
+	for n := 0; n < b.N; n++ {
+		decoder := NewFeedDecoder(bytes.NewReader(data))
+		decoder.Limit = 10
+		if _, err := decoder.Entries(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
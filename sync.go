@@ -0,0 +1,229 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//DefaultSyncTemplate the default file name template for synced videos
+const DefaultSyncTemplate = "{show}/{publish_date} - {name}.mp4"
+
+const syncStateFile = ".gbomb-sync.json"
+
+//ErrNoDownload passed to VideoSync.OnVideo for videos without a rendition the
+//policy allows downloading
+var ErrNoDownload = fmt.Errorf("no downloadable rendition")
+
+var fileNameEscaper = strings.NewReplacer(
+	"/", "_", `\`, "_", ":", "_", "*", "_", "?", "_", `"`, "_", "<", "_", ">", "_", "|", "_",
+)
+
+//VideoSync keeps a local archive of the videos matching a filter
+type VideoSync struct {
+	Invoker *Invoker
+	//Dir the directory videos and the state file are stored in
+	Dir    string
+	Filter VideoFilter
+	//Policy picks the rendition downloaded for each video
+	Policy QualityPolicy
+	//Template names files relative to Dir, {show}, {publish_date}, {name},
+	//{guid} and {id} are replaced, DefaultSyncTemplate if empty
+	Template string
+	//State maps synced videos to their file relative to Dir
+	State map[GUID]string
+	//OnVideo called once a video is handled, err is set if it was not
+	//downloaded
+	OnVideo func(video *VideoInfo, path string, err error)
+	//Progress called as a video downloads
+	Progress func(video *VideoInfo, written, total int64)
+}
+
+//SyncStats counts what a sync did
+type SyncStats struct {
+	Downloaded int
+	//Existing videos already in the archive
+	Existing int
+	//Unavailable videos without a rendition the policy allows downloading
+	Unavailable int
+}
+
+//OpenVideoSync Creates a video sync in dir loading its state if present
+func OpenVideoSync(invoker *Invoker, dir string, filter VideoFilter) (*VideoSync, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &VideoSync{
+		Invoker:  invoker,
+		Dir:      dir,
+		Filter:   filter,
+		Template: DefaultSyncTemplate,
+		State:    make(map[GUID]string),
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, syncStateFile))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &s.State)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+//Save writes the state file to Dir
+func (s *VideoSync) Save() error {
+	data, err := json.MarshalIndent(s.State, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(s.Dir, syncStateFile+".tmp")
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(s.Dir, syncStateFile))
+}
+
+//FileName returns the path of a video relative to Dir
+func (s *VideoSync) FileName(video *VideoInfo) string {
+	template := s.Template
+	if template == "" {
+		template = DefaultSyncTemplate
+	}
+
+	show := video.Show.Title
+	if show == "" {
+		show = "Unknown Show"
+	}
+	published := "unknown"
+	if t, err := video.PublishDate.GetTime(); err == nil {
+		published = t.Format(DateLayout)
+	}
+
+	name := strings.NewReplacer(
+		"{show}", escapeFileName(show),
+		"{publish_date}", published,
+		"{name}", escapeFileName(video.Name),
+		"{guid}", escapeFileName(string(video.GUID)),
+		"{id}", strconv.Itoa(video.ID),
+	).Replace(template)
+
+	return filepath.FromSlash(name)
+}
+
+func escapeFileName(s string) string {
+	return strings.Trim(fileNameEscaper.Replace(s), " .")
+}
+
+//Synced returns if a video is on disk at the path in the state file or at
+//its templated name when the state has no record of it
+func (s *VideoSync) Synced(video *VideoInfo) bool {
+	rel, ok := s.State[video.GUID]
+	if !ok {
+		rel = s.FileName(video)
+	}
+
+	_, err := os.Stat(filepath.Join(s.Dir, rel))
+	return err == nil
+}
+
+//Run pages through every video matching the filter downloading the ones not
+//yet synced, the state is saved after each download so an interrupted run
+//carries on where it stopped
+func (s *VideoSync) Run(ctx context.Context) (SyncStats, error) {
+	var stats SyncStats
+
+	offset := 0
+	for {
+		page, err := s.Invoker.GetVideosFiltered(ctx, offset, s.Filter)
+		if err != nil {
+			return stats, err
+		}
+
+		for idx := range page.Videos {
+			if err := ctx.Err(); err != nil {
+				return stats, err
+			}
+
+			video := &page.Videos[idx]
+			if s.Synced(video) {
+				stats.Existing++
+				continue
+			}
+
+			rel, err := s.SyncVideo(ctx, video)
+			if s.OnVideo != nil {
+				s.OnVideo(video, rel, err)
+			}
+			if err == ErrNoDownload {
+				stats.Unavailable++
+				continue
+			}
+			if err != nil {
+				return stats, err
+			}
+			stats.Downloaded++
+		}
+
+		offset += len(page.Videos)
+		if len(page.Videos) == 0 || offset >= page.MaxResults {
+			return stats, nil
+		}
+	}
+}
+
+//SyncVideo downloads a single video and its JSON sidecar recording it in the
+//state returning its path relative to Dir
+func (s *VideoSync) SyncVideo(ctx context.Context, video *VideoInfo) (string, error) {
+	selection, err := video.SelectURL(s.Policy)
+	if err != nil || !selection.Quality.Downloadable() {
+		return "", ErrNoDownload
+	}
+
+	rel := s.FileName(video)
+	path := filepath.Join(s.Dir, rel)
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	opts := &DownloadOptions{}
+	if s.Progress != nil {
+		opts.Progress = func(written, total int64) {
+			s.Progress(video, written, total)
+		}
+	}
+	err = s.Invoker.DownloadVideoTo(ctx, video, selection.Quality, path, opts)
+	if err != nil {
+		return "", err
+	}
+
+	sidecar, err := json.MarshalIndent(video, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	err = ioutil.WriteFile(strings.TrimSuffix(path, filepath.Ext(path))+".json", sidecar, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	s.State[video.GUID] = filepath.ToSlash(rel)
+
+	return rel, s.Save()
+}
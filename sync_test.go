@@ -0,0 +1,162 @@
+package gbomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//SyncMock serves filtered video listings and video files
+type SyncMock struct {
+	pages     map[string]string
+	files     map[string]string
+	downloads int
+}
+
+func (s *SyncMock) Do(req *http.Request) (*http.Response, error) {
+	q := req.URL.Query()
+	if q.Get("api_key") != "coolbeans" {
+		return nil, fmt.Errorf("missing api key %s", req.URL)
+	}
+
+	body, ok := "", false
+	if req.URL.Path == "/api/videos" {
+		if q.Get("filter") != "video_show:3" {
+			return nil, fmt.Errorf("invalid filter %s", req.URL)
+		}
+		body, ok = s.pages[q.Get("offset")]
+	} else {
+		body, ok = s.files[req.URL.Path]
+		s.downloads++
+	}
+	if !ok {
+		return nil, fmt.Errorf("unexpected URL %s", req.URL)
+	}
+
+	return &http.Response{
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		StatusCode:    http.StatusOK,
+		Status:        http.StatusText(http.StatusOK),
+	}, nil
+}
+
+func videoPage(t *testing.T, offset, total int, videos ...VideoInfo) string {
+	data, err := json.Marshal(VideosResponse{
+		ResponsePage: ResponsePage{
+			Error:       "OK",
+			Limit:       2,
+			Offset:      offset,
+			PageResults: len(videos),
+			MaxResults:  total,
+			StatusCode:  1,
+		},
+		Videos: videos,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestVideoSync(t *testing.T) {
+	show := VideoShow{ID: 3, Title: "Quick Look"}
+	first := VideoInfo{
+		GUID: "2300-1", Name: "Bangai-O: Spirits", Show: show,
+		PublishDate: Date{date: "2008-03-06 13:00:00"},
+		HDURL:       "https://giantbomb.com/video/1_hd.mp4",
+		LowURL:      "https://giantbomb.com/video/1_low.mp4",
+	}
+	youtube := VideoInfo{
+		GUID: "2300-2", Name: "Youtube Only", Show: show, YoutubeID: "dQw4w9WgXcQ",
+	}
+	third := VideoInfo{
+		GUID: "2300-3", Name: "Bangai-O", Show: show,
+		PublishDate: Date{date: "2007-01-01"},
+		LowURL:      "https://giantbomb.com/video/3_low.mp4",
+	}
+
+	client := &SyncMock{
+		pages: map[string]string{
+			"0": videoPage(t, 0, 3, first, youtube),
+			"2": videoPage(t, 2, 3, third),
+		},
+		files: map[string]string{
+			"/video/1_hd.mp4":  "hd video",
+			"/video/1_low.mp4": "low video",
+			"/video/3_low.mp4": "old video",
+		},
+	}
+	invoker := createTestInvoker()
+	invoker.client = client
+
+	dir := t.TempDir()
+	sync, err := OpenVideoSync(invoker, dir, VideoFilter{Show: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var skipped []GUID
+	sync.OnVideo = func(video *VideoInfo, path string, err error) {
+		if err == ErrNoDownload {
+			skipped = append(skipped, video.GUID)
+		}
+	}
+
+	stats, err := sync.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (SyncStats{Downloaded: 2, Unavailable: 1}) {
+		t.Errorf("invalid stats %+v", stats)
+	}
+	if len(skipped) != 1 || skipped[0] != "2300-2" {
+		t.Errorf("invalid skipped videos %v", skipped)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "Quick Look", "2008-03-06 - Bangai-O_ Spirits.mp4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hd video" {
+		t.Errorf("downloaded %q expected the hd rendition", data)
+	}
+
+	sidecar, err := ioutil.ReadFile(filepath.Join(dir, "Quick Look", "2007-01-01 - Bangai-O.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info VideoInfo
+	err = json.Unmarshal(sidecar, &info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.GUID != third.GUID || info.PublishDate != third.PublishDate {
+		t.Errorf("invalid sidecar %+v", info)
+	}
+
+	resumed, err := OpenVideoSync(invoker, dir, VideoFilter{Show: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resumed.State) != 2 {
+		t.Errorf("state has %d videos expected %d", len(resumed.State), 2)
+	}
+
+	stats, err = resumed.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (SyncStats{Existing: 2, Unavailable: 1}) {
+		t.Errorf("invalid rerun stats %+v", stats)
+	}
+	if client.downloads != 2 {
+		t.Errorf("made %d downloads expected %d", client.downloads, 2)
+	}
+}
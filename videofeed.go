@@ -0,0 +1,131 @@
+package gbomb
+
+import (
+	"strconv"
+	"time"
+)
+
+//VideoFeedOptions options for NewVideoFeed
+type VideoFeedOptions struct {
+	//Title of the feed, the first video's show if empty
+	Title       string
+	Description string
+	//Link the feed's web page, the giant bomb site if empty
+	Link string
+	//SelfURL where the feed itself is served, written as its atom:link
+	SelfURL string
+	//Policy picks the rendition each enclosure points at, videos without a
+	//downloadable rendition are left out
+	Policy QualityPolicy
+	//Filter leaves out videos it returns false for
+	Filter func(video *VideoInfo) bool
+	//Sizes enclosure lengths by GUID e.g. from ProbeSize, 0 if missing
+	Sizes map[GUID]int64
+	//EnclosureURL rewrites the URL of each enclosure e.g. through a proxy
+	EnclosureURL func(video *VideoInfo, selection Selection) string
+}
+
+//NewVideoFeed builds a podcast channel from video listings with enclosures
+//pointing at each video's rendition chosen by the policy
+func NewVideoFeed(videos []VideoInfo, opts *VideoFeedOptions) *RSSChannel {
+	if opts == nil {
+		opts = &VideoFeedOptions{}
+	}
+
+	channel := &RSSChannel{
+		Title:       opts.Title,
+		Link:        opts.Link,
+		Description: opts.Description,
+		Language:    "en-us",
+		Author:      "Giant Bomb",
+	}
+	if channel.Link == "" {
+		channel.Link = DefaultSiteURL
+	}
+	if opts.SelfURL != "" {
+		channel.AtomLink = AtomLink{Href: opts.SelfURL, Rel: "self", Type: "application/rss+xml"}
+	}
+
+	for idx := range videos {
+		video := &videos[idx]
+		if opts.Filter != nil && !opts.Filter(video) {
+			continue
+		}
+
+		entry, ok := videoEntry(video, opts)
+		if !ok {
+			continue
+		}
+		channel.Entries = append(channel.Entries, entry)
+
+		if channel.Title == "" {
+			channel.Title = video.Show.Title
+		}
+		if channel.ITunesImage.Href == "" {
+			channel.ITunesImage.Href = showArtwork(&video.Show)
+		}
+	}
+
+	if channel.Title == "" {
+		channel.Title = "Giant Bomb Videos"
+	}
+	if channel.Description == "" {
+		channel.Description = channel.Title
+	}
+	channel.Summary = channel.Description
+
+	return channel
+}
+
+//NewVideosResponseFeed builds a podcast channel from a page of videos
+func NewVideosResponseFeed(videos *VideosResponse, opts *VideoFeedOptions) *RSSChannel {
+	return NewVideoFeed(videos.Videos, opts)
+}
+
+func showArtwork(show *VideoShow) string {
+	for _, img := range []*Image{&show.Image, &show.Logo} {
+		if variant, ok := img.Best(ImageSuper.Width()); ok {
+			return img.URL(variant)
+		}
+	}
+
+	return ""
+}
+
+//videoEntry converts a video to a feed entry returning false if it has no
+//rendition the policy allows downloading
+func videoEntry(video *VideoInfo, opts *VideoFeedOptions) (RSSFeedEntry, bool) {
+	selection, err := video.SelectURL(opts.Policy)
+	if err != nil || !selection.Quality.Downloadable() {
+		return RSSFeedEntry{}, false
+	}
+
+	enclosureURL := selection.URL
+	if opts.EnclosureURL != nil {
+		enclosureURL = opts.EnclosureURL(video, selection)
+	}
+
+	entry := RSSFeedEntry{
+		Title:       video.Name,
+		Link:        video.SiteDetailURL,
+		Description: video.Deck,
+		GUID:        string(video.GUID),
+		Summary:     video.Deck,
+		Author:      video.Hosts,
+		Image:       ITunesImage{Href: showArtwork(&video.Show)},
+		Enclosure: RSSEnclosure{
+			URL:    enclosureURL,
+			Length: opts.Sizes[video.GUID],
+			Type:   "video/mp4",
+		},
+	}
+	if video.LengthSeconds > 0 {
+		entry.DurationStr = strconv.Itoa(video.LengthSeconds)
+	}
+	if published, err := video.PublishDate.GetTime(); err == nil {
+		entry.PublishTime = published
+		entry.PubDateStr = published.Format(time.RFC1123Z)
+	}
+
+	return entry, true
+}
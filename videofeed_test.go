@@ -0,0 +1,92 @@
+package gbomb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewVideoFeed(t *testing.T) {
+	show := VideoShow{
+		Title: "Quick Look",
+		Image: Image{SuperURL: "https://giantbomb1.cbsistatic.com/uploads/scale_large/quick-look.png"},
+	}
+	videos := []VideoInfo{
+		{
+			GUID: "2300-16713", Name: "Quick Look: Cyber Shadow", Deck: "Ninjas, but cyber.",
+			SiteDetailURL: "https://www.giantbomb.com/shows/quick-look-cyber-shadow/2300-16713/",
+			Hosts:         "Jeff Gerstmann", Show: show, LengthSeconds: 2701,
+			PublishDate: Date{date: "2021-01-26 12:00:00"},
+			HighURL:     "https://giantbomb.com/video/16713_high.mp4",
+			HDURL:       "https://giantbomb.com/video/16713_hd.mp4",
+		},
+		{GUID: "2300-16714", Name: "Youtube Only", Show: show, YoutubeID: "dQw4w9WgXcQ"},
+		{
+			GUID: "2300-16715", Name: "Quick Look: Hitman 3", Show: show,
+			LowURL: "https://giantbomb.com/video/16715_low.mp4",
+		},
+	}
+
+	channel := NewVideoFeed(videos, &VideoFeedOptions{
+		Policy:  CapAtHigh,
+		SelfURL: "https://feeds.example.com/quick-look.xml",
+		Sizes:   map[GUID]int64{"2300-16713": 512},
+		EnclosureURL: func(video *VideoInfo, selection Selection) string {
+			return "https://feeds.example.com/download/" + string(video.GUID) + "/" + selection.Quality.String()
+		},
+	})
+
+	if channel.Title != "Quick Look" || len(channel.Entries) != 2 {
+		t.Fatalf("invalid channel %s with %d entries", channel.Title, len(channel.Entries))
+	}
+	if channel.ITunesImage.Href != show.Image.SuperURL {
+		t.Errorf("invalid artwork %s", channel.ITunesImage.Href)
+	}
+
+	var buf bytes.Buffer
+	err := channel.WriteRSS(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		`<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"`,
+		`<itunes:duration>2701</itunes:duration>`,
+		`<atom:link href="https://feeds.example.com/quick-look.xml" rel="self"`,
+		`<guid isPermaLink="false">2300-16713</guid>`,
+		`<pubDate>Tue, 26 Jan 2021 12:00:00 -0800</pubDate>`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("feed missing %s", expected)
+		}
+	}
+
+	parsed, err := decodeFeed(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Title != "Quick Look" || parsed.AtomLink.Rel != "self" || len(parsed.Entries) != 2 {
+		t.Fatalf("invalid round trip %+v", parsed)
+	}
+
+	first := parsed.Entries[0]
+	expected := RSSEnclosure{
+		URL: "https://feeds.example.com/download/2300-16713/high", Length: 512, Type: "video/mp4",
+	}
+	if first.Enclosure != expected {
+		t.Errorf("invalid enclosure %+v", first.Enclosure)
+	}
+	if duration, _ := first.GetDuration(); duration != 2701*time.Second {
+		t.Errorf("invalid duration %s", duration)
+	}
+	if first.Author != "Jeff Gerstmann" || first.Image.Href != show.Image.SuperURL {
+		t.Errorf("invalid itunes elements %+v", first)
+	}
+	if !first.PublishTime.Equal(time.Date(2021, time.January, 26, 20, 0, 0, 0, time.UTC)) {
+		t.Errorf("invalid publish time %s", first.PublishTime)
+	}
+	if parsed.Entries[1].PubDateStr != "" {
+		t.Errorf("undated video given a pubDate %q", parsed.Entries[1].PubDateStr)
+	}
+}